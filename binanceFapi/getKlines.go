@@ -36,7 +36,20 @@ func ClosePrice(klines []KLine) []float64 {
 
 // 获取K线数据
 func GetContractKlines(symbol, cycle string) ([]KLine, error) {
-	url := fmt.Sprintf(config.Cfg.Api.Binance.FApi.Klines, symbol, cycle, config.Cfg.Benchmark.Klines)
+	return GetContractKlinesLimit(symbol, cycle, config.Cfg.Benchmark.Klines)
+}
+
+// 获取指定数量的K线数据
+func GetContractKlinesLimit(symbol, cycle string, limit int) ([]KLine, error) {
+	return GetContractKlinesBefore(symbol, cycle, limit, 0)
+}
+
+// 获取 endTime (ms) 及之前的指定数量K线数据，endTime 为 0 时获取最新K线，用于分页拉取
+func GetContractKlinesBefore(symbol, cycle string, limit int, endTime int64) ([]KLine, error) {
+	url := fmt.Sprintf(config.Cfg.Api.Binance.FApi.Klines, symbol, cycle, limit)
+	if endTime > 0 {
+		url += fmt.Sprintf("&endTime=%d", endTime)
+	}
 	resp, err := http.Get(url)
 	if err != nil {
 		return nil, err
@@ -26,6 +26,11 @@ type SymbolInfo struct {
 	Change          float64
 	NextFundingTime int64
 	RateCycle       int
+	Support         float64
+	Resistance      float64
+	SMCSignal       string
+	Fvg             string
+	Ob              string
 }
 
 type SymbolPrice struct {
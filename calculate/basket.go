@@ -0,0 +1,168 @@
+package calculate
+
+import (
+	"IndicatorTask/binanceFapi"
+	"IndicatorTask/config"
+	"IndicatorTask/utils/logger"
+	"strings"
+)
+
+// 篮子加权方式
+const (
+	BasketEqual     = "equal"
+	BasketMarketCap = "marketcap"
+	BasketVolume    = "volume"
+)
+
+// 篮子指数基点
+const basketBase = 1000.0
+
+// 缓存篮子成分及配对交易对的K线，避免重复请求
+func (r *cycleRun) keepKlines(symbol string, klines []binanceFapi.KLine) {
+	for _, basket := range config.Cfg.Baskets {
+		for _, s := range basket.Symbols {
+			if strings.EqualFold(s, symbol) {
+				r.klineCache[symbol] = klines
+				return
+			}
+		}
+	}
+	for _, pair := range config.Cfg.Pairs {
+		if strings.EqualFold(pair.Base, symbol) || strings.EqualFold(pair.Quote, symbol) {
+			r.klineCache[symbol] = klines
+			return
+		}
+	}
+}
+
+// 获取缓存的K线，未缓存时请求接口
+func (r *cycleRun) cachedKlines(symbol string) ([]binanceFapi.KLine, error) {
+	if klines, ok := r.klineCache[symbol]; ok {
+		return klines, nil
+	}
+	klines, err := getCycleKlines(symbol, r.cycle)
+	if err == nil {
+		r.klineCache[symbol] = klines
+	}
+	return klines, err
+}
+
+// 合成篮子指数K线并按普通交易对流程计算
+func (r *cycleRun) analyzeBaskets() {
+	for _, basket := range config.Cfg.Baskets {
+		name := strings.ToUpper(strings.TrimSpace(basket.Name))
+		if name == "" || len(basket.Symbols) == 0 {
+			continue
+		}
+		constituents := make([][]binanceFapi.KLine, 0, len(basket.Symbols))
+		for _, s := range basket.Symbols {
+			symbol := strings.ToUpper(strings.TrimSpace(s))
+			klines, err := r.cachedKlines(symbol)
+			if err != nil {
+				logger.Log.Error("获取篮子成分K线失败", map[string]interface{}{"basket": name, "symbol": symbol, "err": err})
+				continue
+			}
+			constituents = append(constituents, klines)
+		}
+		if len(constituents) != len(basket.Symbols) {
+			continue
+		}
+
+		klines := buildBasketKlines(constituents, basketWeights(basket, constituents))
+		if len(klines) < config.Cfg.Benchmark.Klines {
+			logger.Log.Warn("篮子数据不足", map[string]interface{}{"basket": name, "count": len(klines), "required": config.Cfg.Benchmark.Klines})
+			continue
+		}
+		r.analyze(&binanceFapi.SymbolInfo{Symbol: name}, klines, true)
+	}
+}
+
+// 各成分权重 (合计为 1)：等权、按成交额 (成分K线平均成交额) 或按市值 (配置的流通量 * 第一根K线收盘价)
+func basketWeights(basket config.Basket, constituents [][]binanceFapi.KLine) []float64 {
+	weights := make([]float64, len(constituents))
+	var total float64
+	for i, klines := range constituents {
+		switch basket.Weighting {
+		case BasketVolume:
+			for _, k := range klines {
+				weights[i] += k.QuoteVolume
+			}
+		case BasketMarketCap:
+			for symbol, supply := range basket.Supply {
+				if strings.EqualFold(symbol, basket.Symbols[i]) && len(klines) > 0 {
+					weights[i] = supply * klines[0].Close
+				}
+			}
+		default:
+			weights[i] = 1
+		}
+		total += weights[i]
+	}
+	for i := range weights {
+		if total > 0 {
+			weights[i] /= total
+		} else {
+			weights[i] = 1 / float64(len(weights))
+		}
+	}
+	return weights
+}
+
+// 在全部成分共有的K线上合成指数：各成分价格以首根共有K线收盘价归一后按权重加总
+// 高低点按成分高低点加权近似，成交额与主动买入成交额直接加总，成交量按指数收盘价折算
+func buildBasketKlines(constituents [][]binanceFapi.KLine, weights []float64) []binanceFapi.KLine {
+	if len(constituents) == 0 {
+		return nil
+	}
+	byTime := make([]map[int64]binanceFapi.KLine, len(constituents))
+	for i, klines := range constituents {
+		byTime[i] = make(map[int64]binanceFapi.KLine, len(klines))
+		for _, k := range klines {
+			byTime[i][k.OpenTime] = k
+		}
+	}
+
+	var res []binanceFapi.KLine
+	var base []float64
+	for _, k0 := range constituents[0] {
+		bar := make([]binanceFapi.KLine, len(constituents))
+		complete := true
+		for i := range constituents {
+			k, ok := byTime[i][k0.OpenTime]
+			if !ok || k.Close <= 0 {
+				complete = false
+				break
+			}
+			bar[i] = k
+		}
+		if !complete {
+			continue
+		}
+		if base == nil {
+			base = make([]float64, len(bar))
+			for i, k := range bar {
+				base[i] = k.Close
+			}
+		}
+
+		idx := binanceFapi.KLine{OpenTime: k0.OpenTime, CloseTime: k0.CloseTime}
+		var takerQuote float64
+		for i, k := range bar {
+			w := weights[i] * basketBase / base[i]
+			idx.Open += w * k.Open
+			idx.High += w * k.High
+			idx.Low += w * k.Low
+			idx.Close += w * k.Close
+			idx.QuoteVolume += k.QuoteVolume
+			idx.NumTrades += k.NumTrades
+			takerQuote += k.TakerBuyQuoteVol
+		}
+		if idx.Close > 0 {
+			idx.Volume = idx.QuoteVolume / idx.Close
+			idx.TakerBuyVolume = takerQuote / idx.Close
+		}
+		idx.TakerBuyQuoteVol = takerQuote
+		res = append(res, idx)
+	}
+	return res
+}
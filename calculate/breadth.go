@@ -0,0 +1,225 @@
+package calculate
+
+import (
+	"IndicatorTask/binanceFapi"
+	"IndicatorTask/config"
+	"IndicatorTask/store"
+	"IndicatorTask/utils/logger"
+	"IndicatorTask/utils/notify"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cryptoSelect/public/database"
+)
+
+// 宽度统计的 EMA 周期
+var breadthEmaPeriods = []int{20, 50, 200}
+
+// 单个交易对在本轮的宽度数据 (以最后一根已收盘K线为准)
+type breadthRow struct {
+	BarTime  int64        // 最后一根已收盘K线开盘时间 (ms)
+	AboveEma map[int]bool // EMA 周期 -> 收盘是否在其上方，K线不足的周期不包含
+	Change   float64      // 涨跌幅，>0 为上涨家数
+	NewHigh  bool         // 收盘创 N 周期新高
+	NewLow   bool         // 收盘创 N 周期新低
+	Rsi      float64
+}
+
+func newBreadthRow(klines []binanceFapi.KLine, params config.Breadth) breadthRow {
+	if params.HighLowPeriod <= 0 {
+		params.HighLowPeriod = 50
+	}
+	row := breadthRow{AboveEma: make(map[int]bool)}
+	closed := closedKlines(klines)
+	n := len(closed)
+	if n < 2 {
+		return row
+	}
+	last := closed[n-1]
+	row.BarTime = last.OpenTime
+	row.Change = last.Close - closed[n-2].Close
+
+	closes := binanceFapi.ClosePrice(closed)
+	for _, period := range breadthEmaPeriods {
+		if n >= period {
+			ema := calculateEMA(closes, period)
+			row.AboveEma[period] = last.Close > ema[n-1]
+		}
+	}
+	if n > params.HighLowPeriod {
+		row.NewHigh, row.NewLow = true, true
+		for i := n - 1 - params.HighLowPeriod; i < n-1; i++ {
+			if closed[i].High >= last.Close {
+				row.NewHigh = false
+			}
+			if closed[i].Low <= last.Close {
+				row.NewLow = false
+			}
+		}
+	}
+	row.Rsi = calculateRsiSeries(closes, config.Cfg.Benchmark.Rsi.Period)[n-1]
+	return row
+}
+
+// 百分比，分母为 0 时返回 0
+func percent(count, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(count) / float64(total) * 100
+}
+
+// 所有交易对K线均不足该 EMA 周期时无法统计，返回 nil
+func eligiblePercent(count, total int) *float64 {
+	if total == 0 {
+		return nil
+	}
+	pct := percent(count, total)
+	return &pct
+}
+
+// 宽度占比描述，无法统计时为 -
+func breadthPctFmt(pct *float64) string {
+	if pct == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", *pct)
+}
+
+// 汇总本轮宽度数据，衔接上一条记录计算 A/D 线与 McClellan 振荡器，入库并在进入极值时提示
+func runBreadth(cycle string, rows []breadthRow, params config.Breadth) {
+	if params.ExtremeHigh <= 0 {
+		params.ExtremeHigh = 80
+	}
+	if params.ExtremeLow <= 0 {
+		params.ExtremeLow = 20
+	}
+	if params.RsiExtreme <= 0 {
+		params.RsiExtreme = 30
+	}
+	if params.McClellanExtreme <= 0 {
+		params.McClellanExtreme = 70
+	}
+	if len(rows) == 0 {
+		return
+	}
+	rec := store.BreadthRecord{Cycle: cycle, Total: len(rows)}
+	above, eligible := make(map[int]int), make(map[int]int)
+	top, low := float64(config.Cfg.Benchmark.Rsi.Top), float64(config.Cfg.Benchmark.Rsi.Low)
+	if top <= 0 {
+		top = 70
+	}
+	if low <= 0 {
+		low = 30
+	}
+	var oversold, overbought int
+	for _, r := range rows {
+		if r.BarTime > rec.BarTime.UnixMilli() {
+			rec.BarTime = time.UnixMilli(r.BarTime)
+		}
+		for period, ok := range r.AboveEma {
+			eligible[period]++
+			if ok {
+				above[period]++
+			}
+		}
+		switch {
+		case r.Change > 0:
+			rec.Advances++
+		case r.Change < 0:
+			rec.Declines++
+		}
+		if r.NewHigh {
+			rec.NewHighs++
+		}
+		if r.NewLow {
+			rec.NewLows++
+		}
+		if r.Rsi >= top {
+			overbought++
+		} else if r.Rsi <= low {
+			oversold++
+		}
+	}
+	rec.Above20 = eligiblePercent(above[20], eligible[20])
+	rec.Above50 = eligiblePercent(above[50], eligible[50])
+	rec.Above200 = eligiblePercent(above[200], eligible[200])
+	rec.OversoldPct = percent(oversold, len(rows))
+	rec.OverboughtPct = percent(overbought, len(rows))
+
+	// 同一根K线已统计过则跳过
+	var count int64
+	err := database.DB.Model(&store.BreadthRecord{}).Where("cycle = ? AND bar_time = ?", cycle, rec.BarTime).Count(&count).Error
+	if err != nil || count > 0 {
+		return
+	}
+
+	// McClellan：比例调整后的净上涨家数 19/39 EMA 之差
+	net := 0.0
+	if rec.Advances+rec.Declines > 0 {
+		net = float64(rec.Advances-rec.Declines) / float64(rec.Advances+rec.Declines) * 1000
+	}
+	var prev store.BreadthRecord
+	hasPrev := database.DB.Where("cycle = ?", cycle).Order("bar_time DESC").Limit(1).Find(&prev).RowsAffected > 0
+	if hasPrev {
+		rec.AdLine = prev.AdLine + float64(rec.Advances-rec.Declines)
+		rec.Ema19 = net*0.1 + prev.Ema19*0.9
+		rec.Ema39 = net*0.05 + prev.Ema39*0.95
+	} else {
+		rec.AdLine = float64(rec.Advances - rec.Declines)
+		rec.Ema19, rec.Ema39 = net, net
+	}
+	rec.McClellan = rec.Ema19 - rec.Ema39
+
+	if err := database.DB.Create(&rec).Error; err != nil {
+		logger.Log.Error("保存市场宽度失败", map[string]interface{}{"cycle": cycle, "err": err})
+		return
+	}
+
+	if signals := breadthExtremes(rec, prev, hasPrev, params); len(signals) > 0 && config.Cfg.Notify.IsEnable {
+		notify.SendTelegramMessage(cycle, breadthMsgFmt(rec, signals))
+	}
+}
+
+// 本轮新进入的极值状态 (上一轮已处于极值时不重复提示)
+func breadthExtremes(rec, prev store.BreadthRecord, hasPrev bool, params config.Breadth) []string {
+	var signals []string
+	check := func(extreme, lastExtreme bool, msg string) {
+		if extreme && (!hasPrev || !lastExtreme) {
+			signals = append(signals, msg)
+		}
+	}
+	if rec.Above50 != nil {
+		above50, prevAbove50 := *rec.Above50, -1.0
+		if prev.Above50 != nil {
+			prevAbove50 = *prev.Above50
+		}
+		check(above50 >= params.ExtremeHigh, prevAbove50 >= params.ExtremeHigh,
+			fmt.Sprintf("EMA50上方占比过高 %.1f%%", above50))
+		check(above50 <= params.ExtremeLow, prevAbove50 >= 0 && prevAbove50 <= params.ExtremeLow,
+			fmt.Sprintf("EMA50上方占比过低 %.1f%%", above50))
+	}
+	check(rec.OversoldPct >= params.RsiExtreme, prev.OversoldPct >= params.RsiExtreme,
+		fmt.Sprintf("RSI超卖占比 %.1f%%", rec.OversoldPct))
+	check(rec.OverboughtPct >= params.RsiExtreme, prev.OverboughtPct >= params.RsiExtreme,
+		fmt.Sprintf("RSI超买占比 %.1f%%", rec.OverboughtPct))
+	check(rec.McClellan >= params.McClellanExtreme, prev.McClellan >= params.McClellanExtreme,
+		fmt.Sprintf("McClellan 超买 %.1f", rec.McClellan))
+	check(rec.McClellan <= -params.McClellanExtreme, prev.McClellan <= -params.McClellanExtreme,
+		fmt.Sprintf("McClellan 超卖 %.1f", rec.McClellan))
+	return signals
+}
+
+// 市场宽度提醒
+func breadthMsgFmt(rec store.BreadthRecord, signals []string) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("     ---- 【  市场宽度 %s  】 ---- \n", rec.Cycle))
+	builder.WriteString(fmt.Sprintf("信号: %s\n", strings.Join(signals, ", ")))
+	builder.WriteString(fmt.Sprintf("EMA上方: 20=%s 50=%s 200=%s\n", breadthPctFmt(rec.Above20), breadthPctFmt(rec.Above50), breadthPctFmt(rec.Above200)))
+	builder.WriteString(fmt.Sprintf("涨跌家数: %d/%d A/D线: %.0f\n", rec.Advances, rec.Declines, rec.AdLine))
+	builder.WriteString(fmt.Sprintf("新高/新低: %d/%d\n", rec.NewHighs, rec.NewLows))
+	builder.WriteString(fmt.Sprintf("RSI超卖/超买: %.1f%%/%.1f%%\n", rec.OversoldPct, rec.OverboughtPct))
+	builder.WriteString(fmt.Sprintf("McClellan: %.1f\n", rec.McClellan))
+	return builder.String()
+}
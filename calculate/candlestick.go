@@ -0,0 +1,235 @@
+package calculate
+
+import (
+	"IndicatorTask/binanceFapi"
+	"IndicatorTask/config"
+	"time"
+)
+
+// CandlePattern K线形态
+type CandlePattern struct {
+	Name       string
+	Bias       int    // 0: 中性, 1: 看涨, 2: 看跌
+	Context    string // 形态所处位置：波段高点 / 波段低点 / 支撑位 / 压力位
+	Meaningful bool   // 方向与位置匹配 (看涨形态在低位、看跌形态在高位、中性形态在任一关键位)
+}
+
+// 去掉尚未收盘的最后一根K线
+func closedKlines(klines []binanceFapi.KLine) []binanceFapi.KLine {
+	n := len(klines)
+	if n > 0 && klines[n-1].CloseTime > time.Now().UnixMilli() {
+		return klines[:n-1]
+	}
+	return klines
+}
+
+// 单根K线的实体与影线
+type candleShape struct {
+	Body      float64
+	Range     float64
+	Upper     float64
+	Lower     float64
+	Bullish   bool
+	BodyHigh  float64
+	BodyLow   float64
+	MidPrice  float64
+	HighPrice float64
+	LowPrice  float64
+}
+
+func shapeOf(k binanceFapi.KLine) candleShape {
+	s := candleShape{
+		Body:      abs(k.Close - k.Open),
+		Range:     k.High - k.Low,
+		Bullish:   k.Close > k.Open,
+		BodyHigh:  max(k.Open, k.Close),
+		BodyLow:   min(k.Open, k.Close),
+		HighPrice: k.High,
+		LowPrice:  k.Low,
+	}
+	s.Upper = k.High - s.BodyHigh
+	s.Lower = s.BodyLow - k.Low
+	s.MidPrice = (k.Open + k.Close) / 2
+	return s
+}
+
+// 识别最后一根 (已收盘) K线结尾的形态
+func recognizeCandles(klines []binanceFapi.KLine) []CandlePattern {
+	n := len(klines)
+	if n < 3 {
+		return nil
+	}
+	c1, c2, c3 := shapeOf(klines[n-3]), shapeOf(klines[n-2]), shapeOf(klines[n-1])
+	if c3.Range == 0 {
+		return nil
+	}
+
+	// 以最近 10 根平均实体衡量大小
+	var avgBody float64
+	count := 0
+	for i := n - 1; i >= 0 && i >= n-10; i-- {
+		avgBody += shapeOf(klines[i]).Body
+		count++
+	}
+	avgBody /= float64(count)
+	tolerance := c3.Range * 0.05
+
+	var patterns []CandlePattern
+	add := func(name string, bias int) {
+		patterns = append(patterns, CandlePattern{Name: name, Bias: bias})
+	}
+
+	// 十字星
+	if c3.Body <= c3.Range*0.1 {
+		switch {
+		case c3.Upper <= c3.Range*0.1:
+			add("蜻蜓十字", 1)
+		case c3.Lower <= c3.Range*0.1:
+			add("墓碑十字", 2)
+		case c3.Range > avgBody*2:
+			add("长腿十字", 0)
+		default:
+			add("十字星", 0)
+		}
+	} else {
+		// 锤子线：长下影，实体在上端 (出现在高位时为上吊线)
+		if c3.Lower >= c3.Body*2 && c3.Upper <= c3.Body*0.5 {
+			add("锤子线", 1)
+		}
+		// 射击之星：长上影，实体在下端 (出现在低位时为倒锤子)
+		if c3.Upper >= c3.Body*2 && c3.Lower <= c3.Body*0.5 {
+			add("射击之星", 2)
+		}
+	}
+
+	// Pin Bar：影线占全长 2/3 以上
+	if c3.Lower >= c3.Range*2/3 {
+		add("看涨PinBar", 1)
+	} else if c3.Upper >= c3.Range*2/3 {
+		add("看跌PinBar", 2)
+	}
+
+	// 吞没
+	if c3.Bullish && !c2.Bullish && c3.BodyHigh > c2.BodyHigh && c3.BodyLow < c2.BodyLow {
+		add("看涨吞没", 1)
+	}
+	if !c3.Bullish && c2.Bullish && c3.BodyHigh > c2.BodyHigh && c3.BodyLow < c2.BodyLow {
+		add("看跌吞没", 2)
+	}
+
+	// 孕线
+	if c2.Body > avgBody && c3.BodyHigh < c2.BodyHigh && c3.BodyLow > c2.BodyLow {
+		if !c2.Bullish && c3.Bullish {
+			add("看涨孕线", 1)
+		} else if c2.Bullish && !c3.Bullish {
+			add("看跌孕线", 2)
+		}
+	}
+
+	// 镊子顶 / 镊子底
+	if abs(c3.HighPrice-c2.HighPrice) <= tolerance && c2.Bullish && !c3.Bullish {
+		add("镊子顶", 2)
+	}
+	if abs(c3.LowPrice-c2.LowPrice) <= tolerance && !c2.Bullish && c3.Bullish {
+		add("镊子底", 1)
+	}
+
+	// 早晨之星 / 黄昏之星
+	if c1.Body > avgBody && c2.Body < c1.Body*0.3 && c3.Body > avgBody*0.5 {
+		if !c1.Bullish && c3.Bullish && c2.BodyHigh < c1.BodyLow+c1.Body*0.5 && klines[n-1].Close > c1.MidPrice {
+			add("早晨之星", 1)
+		}
+		if c1.Bullish && !c3.Bullish && c2.BodyLow > c1.BodyHigh-c1.Body*0.5 && klines[n-1].Close < c1.MidPrice {
+			add("黄昏之星", 2)
+		}
+	}
+
+	// 红三兵 / 三只乌鸦
+	k1, k2, k3 := klines[n-3], klines[n-2], klines[n-1]
+	if c1.Bullish && c2.Bullish && c3.Bullish && k2.Close > k1.Close && k3.Close > k2.Close &&
+		k2.Open > c1.BodyLow && k3.Open > c2.BodyLow && c3.Upper < c3.Body*0.5 {
+		add("红三兵", 1)
+	}
+	if !c1.Bullish && !c2.Bullish && !c3.Bullish && k2.Close < k1.Close && k3.Close < k2.Close &&
+		k2.Open < c1.BodyHigh && k3.Open < c2.BodyHigh && c3.Lower < c3.Body*0.5 {
+		add("三只乌鸦", 2)
+	}
+
+	return patterns
+}
+
+// 识别K线形态并按位置过滤：形态K线处于回看区间高/低点，或靠近支撑压力位
+// levels 为参考的支撑压力价位 (如 SMC 支撑压力、缠论中枢上下沿)
+func detectCandlePatterns(rawKlines []binanceFapi.KLine, levels []float64, params config.Candle) []CandlePattern {
+	if params.Lookback <= 0 {
+		params.Lookback = 10
+	}
+	if params.Tolerance <= 0 {
+		params.Tolerance = 0.5
+	}
+	klines := closedKlines(rawKlines)
+	n := len(klines)
+	if n < params.Lookback+1 {
+		return nil
+	}
+
+	patterns := recognizeCandles(klines)
+	if len(patterns) == 0 {
+		return nil
+	}
+
+	// 形态最后两根K线的高低点与回看区间比较
+	last, prev := klines[n-1], klines[n-2]
+	patternHigh := max(last.High, prev.High)
+	patternLow := min(last.Low, prev.Low)
+	atHigh, atLow := true, true
+	for i := n - params.Lookback; i < n-2; i++ {
+		if klines[i].High > patternHigh {
+			atHigh = false
+		}
+		if klines[i].Low < patternLow {
+			atLow = false
+		}
+	}
+
+	nearSupport, nearResistance := false, false
+	tolerance := params.Tolerance / 100
+	for _, level := range levels {
+		if level <= 0 {
+			continue
+		}
+		if abs(patternLow-level) <= level*tolerance && last.Close >= level {
+			nearSupport = true
+		}
+		if abs(patternHigh-level) <= level*tolerance && last.Close <= level {
+			nearResistance = true
+		}
+	}
+
+	low := atLow || nearSupport
+	high := atHigh || nearResistance
+
+	var res []CandlePattern
+	for _, p := range patterns {
+		// 同形异名：高位锤子线为上吊线，低位射击之星为倒锤子
+		if p.Name == "锤子线" && high && !low {
+			p.Name, p.Bias = "上吊线", 2
+		} else if p.Name == "射击之星" && low && !high {
+			p.Name, p.Bias = "倒锤子", 1
+		}
+
+		switch {
+		case atLow:
+			p.Context = "波段低点"
+		case nearSupport:
+			p.Context = "支撑位"
+		case atHigh:
+			p.Context = "波段高点"
+		case nearResistance:
+			p.Context = "压力位"
+		}
+		p.Meaningful = (p.Bias == 1 && low) || (p.Bias == 2 && high) || (p.Bias == 0 && (low || high))
+		res = append(res, p)
+	}
+	return res
+}
@@ -0,0 +1,289 @@
+package calculate
+
+import (
+	"IndicatorTask/binanceFapi"
+	"IndicatorTask/config"
+)
+
+// 缠论分型点 (包含处理后)
+type chanFractal struct {
+	Type  int     // 1: 顶分型, 2: 底分型
+	Pos   int     // 包含处理后K线下标
+	Index int     // 原始K线下标
+	Price float64 // 顶分型取高点，底分型取低点
+}
+
+// 笔 / 线段，由起止两个分型点构成
+type chanStroke struct {
+	Start     chanFractal
+	End       chanFractal
+	Direction int // 1: 向上, 2: 向下
+}
+
+func (s chanStroke) High() float64 {
+	return max(s.Start.Price, s.End.Price)
+}
+
+func (s chanStroke) Low() float64 {
+	return min(s.Start.Price, s.End.Price)
+}
+
+// 中枢
+type chanPivot struct {
+	ZG          float64 // 中枢上沿 (重叠区间高点)
+	ZD          float64 // 中枢下沿 (重叠区间低点)
+	GG          float64 // 中枢波动最高点
+	DD          float64 // 中枢波动最低点
+	StrokeStart int     // 构成中枢的第一笔下标
+	StrokeEnd   int     // 构成中枢的最后一笔下标
+}
+
+// ChanResult 缠论结构
+type ChanResult struct {
+	Strokes    []chanStroke
+	Segments   []chanStroke
+	Pivots     []chanPivot
+	PivotBreak int // 0: 无, 1: 最新K线向上离开中枢, 2: 向下离开中枢
+}
+
+// 在包含处理后的K线上识别全部分型
+func findChanFractals(processed []chanKLine) []chanFractal {
+	var fractals []chanFractal
+	for i := 1; i < len(processed)-1; i++ {
+		k1, k2, k3 := processed[i-1], processed[i], processed[i+1]
+		if k2.High > k1.High && k2.High > k3.High {
+			fractals = append(fractals, chanFractal{Type: 1, Pos: i, Index: k2.HighIdx, Price: k2.High})
+		} else if k2.Low < k1.Low && k2.Low < k3.Low {
+			fractals = append(fractals, chanFractal{Type: 2, Pos: i, Index: k2.LowIdx, Price: k2.Low})
+		}
+	}
+	return fractals
+}
+
+// 顶底交替连接端点：同类取更极端者，异类需满足最小间隔且顶高于底
+// gap 为相邻端点在 pos 上的最小间隔
+func connectFractals(fractals []chanFractal, gap int, pos func(chanFractal) int) []chanFractal {
+	var points []chanFractal
+	for _, f := range fractals {
+		if len(points) == 0 {
+			points = append(points, f)
+			continue
+		}
+		last := points[len(points)-1]
+		if f.Type == last.Type {
+			if (f.Type == 1 && f.Price > last.Price) || (f.Type == 2 && f.Price < last.Price) {
+				points[len(points)-1] = f
+			}
+			continue
+		}
+		if pos(f)-pos(last) < gap {
+			continue
+		}
+		if (f.Type == 1 && f.Price <= last.Price) || (f.Type == 2 && f.Price >= last.Price) {
+			continue
+		}
+		points = append(points, f)
+	}
+	return points
+}
+
+// 端点序列转为笔/线段
+func toStrokes(points []chanFractal) []chanStroke {
+	var strokes []chanStroke
+	for i := 1; i < len(points); i++ {
+		dir := 1
+		if points[i].Type == 2 {
+			dir = 2
+		}
+		strokes = append(strokes, chanStroke{Start: points[i-1], End: points[i], Direction: dir})
+	}
+	return strokes
+}
+
+// 特征序列元素：线段内与线段方向相反的笔 (向上线段取向下笔)，Stroke 为提供极值的笔
+type featureElement struct {
+	High   float64
+	Low    float64
+	Stroke int
+}
+
+// 特征序列分型，Stroke 为分型顶 (底) 元素对应的笔，Gap 表示第一、二元素间存在缺口
+type featureFractal struct {
+	Stroke int
+	Price  float64
+	Gap    bool
+}
+
+// 从 from 笔开始、方向为 dir 的线段的特征序列分型：先按线段方向做包含处理，向上线段找顶分型，向下线段找底分型
+func featureFractals(strokes []chanStroke, from, dir int) []featureFractal {
+	var seq []featureElement
+	for j := from + 1; j < len(strokes); j += 2 {
+		e := featureElement{High: strokes[j].High(), Low: strokes[j].Low(), Stroke: j}
+		if n := len(seq); n > 0 {
+			last := &seq[n-1]
+			if (last.High >= e.High && last.Low <= e.Low) || (e.High >= last.High && e.Low <= last.Low) {
+				if dir == 1 {
+					if e.High > last.High {
+						last.Stroke = e.Stroke
+					}
+					last.High, last.Low = max(last.High, e.High), max(last.Low, e.Low)
+				} else {
+					if e.Low < last.Low {
+						last.Stroke = e.Stroke
+					}
+					last.High, last.Low = min(last.High, e.High), min(last.Low, e.Low)
+				}
+				continue
+			}
+		}
+		seq = append(seq, e)
+	}
+
+	var res []featureFractal
+	for k := 1; k+1 < len(seq); k++ {
+		a, b, c := seq[k-1], seq[k], seq[k+1]
+		if dir == 1 && b.High > a.High && b.High > c.High {
+			res = append(res, featureFractal{Stroke: b.Stroke, Price: b.High, Gap: b.Low > a.High})
+		} else if dir == 2 && b.Low < a.Low && b.Low < c.Low {
+			res = append(res, featureFractal{Stroke: b.Stroke, Price: b.Low, Gap: b.High < a.Low})
+		}
+	}
+	return res
+}
+
+// 线段结束位置：返回线段最后一笔的下标。特征序列出现分型即结束；
+// 分型第一、二元素间有缺口时，需反向线段的特征序列也出现分型，且期间未突破分型极值才确认
+func segmentEnd(strokes []chanStroke, from int) (int, bool) {
+	dir := strokes[from].Direction
+	opposite := 3 - dir
+	for _, f := range featureFractals(strokes, from, dir) {
+		if !f.Gap {
+			return f.Stroke - 1, true
+		}
+		confirms := featureFractals(strokes, f.Stroke, opposite)
+		if len(confirms) == 0 {
+			continue
+		}
+		broken := false
+		for j := f.Stroke; j <= confirms[0].Stroke; j++ {
+			if (dir == 1 && strokes[j].High() > f.Price) || (dir == 2 && strokes[j].Low() < f.Price) {
+				broken = true
+				break
+			}
+		}
+		if !broken {
+			return f.Stroke - 1, true
+		}
+	}
+	return 0, false
+}
+
+// from 笔开始的线段在 limit 笔之前被反向笔突破起点，返回突破的笔
+func segmentStartBroken(strokes []chanStroke, from, limit int) (int, bool) {
+	start := strokes[from].Start.Price
+	for j := from + 1; j <= limit && j < len(strokes); j += 2 {
+		price := strokes[j].End.Price
+		if (strokes[from].Direction == 1 && price < start) || (strokes[from].Direction == 2 && price > start) {
+			return j, true
+		}
+	}
+	return 0, false
+}
+
+// 按特征序列划分线段：线段至少包含 3 笔，最后未完成的线段取其后同向笔的极值端点
+func buildSegments(strokes []chanStroke) []chanStroke {
+	if len(strokes) < 3 {
+		return nil
+	}
+	points := []chanFractal{strokes[0].Start}
+	from := 0
+	for from+2 < len(strokes) {
+		end, ok := segmentEnd(strokes, from)
+		limit := len(strokes) - 1
+		if ok {
+			limit = end
+		}
+		// 线段完成前反向笔突破线段起点，说明前一线段尚未结束，延伸至该笔终点后重新划分
+		if j, broken := segmentStartBroken(strokes, from, limit); broken {
+			points[len(points)-1] = strokes[j].End
+			from = j + 1
+			continue
+		}
+		if !ok {
+			break
+		}
+		points = append(points, strokes[end].End)
+		from = end + 1
+	}
+
+	// 未完成的线段
+	if from+2 < len(strokes) {
+		dir := strokes[from].Direction
+		last := strokes[from].End
+		for j := from; j < len(strokes); j += 2 {
+			if end := strokes[j].End; (dir == 1 && end.Price > last.Price) || (dir == 2 && end.Price < last.Price) {
+				last = end
+			}
+		}
+		points = append(points, last)
+	}
+	return toStrokes(points)
+}
+
+// 由连续三笔重叠区间构建中枢，后续笔与中枢区间重叠则延伸
+func buildPivots(strokes []chanStroke) []chanPivot {
+	var pivots []chanPivot
+	i := 0
+	for i+2 < len(strokes) {
+		zg := min(strokes[i].High(), min(strokes[i+1].High(), strokes[i+2].High()))
+		zd := max(strokes[i].Low(), max(strokes[i+1].Low(), strokes[i+2].Low()))
+		if zg <= zd {
+			i++
+			continue
+		}
+		pivot := chanPivot{ZG: zg, ZD: zd, GG: zg, DD: zd, StrokeStart: i, StrokeEnd: i + 2}
+		j := i
+		for ; j < len(strokes); j++ {
+			if j > i+2 && (strokes[j].Low() >= zg || strokes[j].High() <= zd) {
+				break
+			}
+			pivot.GG = max(pivot.GG, strokes[j].High())
+			pivot.DD = min(pivot.DD, strokes[j].Low())
+			pivot.StrokeEnd = j
+		}
+		pivots = append(pivots, pivot)
+		i = j
+	}
+	return pivots
+}
+
+// 构建缠论结构：分型 -> 笔 -> 线段 -> 中枢
+func buildChanStructure(klines []binanceFapi.KLine, params config.Chan) ChanResult {
+	res := ChanResult{}
+	n := len(klines)
+	if n < 10 {
+		return res
+	}
+	if params.MinGap <= 0 {
+		params.MinGap = 4
+	}
+
+	processed := processInclusion(klines)
+	fractals := findChanFractals(processed)
+	points := connectFractals(fractals, params.MinGap, func(f chanFractal) int { return f.Pos })
+	res.Strokes = toStrokes(points)
+	res.Segments = buildSegments(res.Strokes)
+	res.Pivots = buildPivots(res.Strokes)
+
+	// 最新K线收盘离开最近中枢
+	if len(res.Pivots) > 0 {
+		pivot := res.Pivots[len(res.Pivots)-1]
+		curr, prev := klines[n-1].Close, klines[n-2].Close
+		if curr > pivot.ZG && prev <= pivot.ZG {
+			res.PivotBreak = 1
+		} else if curr < pivot.ZD && prev >= pivot.ZD {
+			res.PivotBreak = 2
+		}
+	}
+	return res
+}
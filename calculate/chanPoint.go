@@ -0,0 +1,143 @@
+package calculate
+
+// ChanPoint 缠论买卖点
+type ChanPoint struct {
+	Side     int     // 1: 买点, 2: 卖点
+	Class    int     // 1/2/3 类
+	Stroke   int     // 买卖点所在笔 (以该笔终点为买卖点)
+	Price    float64 // 买卖点价格
+	Pivot    chanPivot
+	Area     float64 // 一类买卖点：离开笔的 MACD 面积
+	PrevArea float64 // 一类买卖点：进入笔的 MACD 面积
+}
+
+// 笔区间内同向 MACD 柱面积 (向下笔取绿柱，向上笔取红柱)
+func strokeArea(stroke chanStroke, histogram []float64) float64 {
+	var area float64
+	for i := stroke.Start.Index; i <= stroke.End.Index && i < len(histogram); i++ {
+		if stroke.Direction == 1 && histogram[i] > 0 {
+			area += histogram[i]
+		} else if stroke.Direction == 2 && histogram[i] < 0 {
+			area -= histogram[i]
+		}
+	}
+	return area
+}
+
+// 第 k 笔直接离开的中枢：中枢在第 k-1 笔结束，或第 k 笔起点仍在中枢内被计入时截取到第 k-1 笔；
+// 与第 k 笔之间隔有其他笔的更早中枢不算
+func pivotBefore(strokes []chanStroke, pivots []chanPivot, k int) (chanPivot, bool) {
+	for i := len(pivots) - 1; i >= 0; i-- {
+		pivot := pivots[i]
+		if pivot.StrokeStart > k-3 {
+			continue
+		}
+		if pivot.StrokeEnd < k-1 {
+			break
+		}
+		pivot.StrokeEnd = k - 1
+		pivot.GG, pivot.DD = pivot.ZG, pivot.ZD
+		for j := pivot.StrokeStart; j <= pivot.StrokeEnd; j++ {
+			pivot.GG = max(pivot.GG, strokes[j].High())
+			pivot.DD = min(pivot.DD, strokes[j].Low())
+		}
+		return pivot, true
+	}
+	return chanPivot{}, false
+}
+
+// 中枢所处趋势：与前一个中枢不重叠且依次降低为下跌 (2)，依次升高为上涨 (1)，否则为盘整 (0)
+func pivotTrend(pivots []chanPivot, pivot chanPivot) int {
+	for i := len(pivots) - 1; i > 0; i-- {
+		if pivots[i].StrokeStart != pivot.StrokeStart {
+			continue
+		}
+		prev := pivots[i-1]
+		if pivot.ZG < prev.ZD {
+			return 2
+		}
+		if pivot.ZD > prev.ZG {
+			return 1
+		}
+		return 0
+	}
+	return 0
+}
+
+// 第 k 笔是否构成一类买卖点：下跌 (上涨) 趋势中离开最后一个中枢的笔创新低/新高，
+// 但 MACD 面积小于进入中枢的同向笔 (面积背离)；盘整中的背驰不算一类买卖点
+func firstClassPoint(strokes []chanStroke, pivots []chanPivot, histogram []float64, k int) (ChanPoint, bool) {
+	if k < 0 || k >= len(strokes) {
+		return ChanPoint{}, false
+	}
+	pivot, ok := pivotBefore(strokes, pivots, k)
+	if !ok {
+		return ChanPoint{}, false
+	}
+
+	leave := strokes[k]
+	if pivotTrend(pivots, pivot) != leave.Direction {
+		return ChanPoint{}, false
+	}
+	// 进入中枢的同向笔：中枢第一笔的前一笔，或中枢第一笔本身
+	enterIdx := pivot.StrokeStart - 1
+	if enterIdx < 0 || strokes[enterIdx].Direction != leave.Direction {
+		enterIdx = pivot.StrokeStart
+	}
+	enter := strokes[enterIdx]
+	if enter.Direction != leave.Direction {
+		return ChanPoint{}, false
+	}
+
+	area, prevArea := strokeArea(leave, histogram), strokeArea(enter, histogram)
+	point := ChanPoint{Class: 1, Stroke: k, Price: leave.End.Price, Pivot: pivot, Area: area, PrevArea: prevArea}
+	if area >= prevArea {
+		return ChanPoint{}, false
+	}
+	if leave.Direction == 2 && leave.End.Price < pivot.DD && leave.End.Price < enter.End.Price {
+		point.Side = 1
+		return point, true
+	}
+	if leave.Direction == 1 && leave.End.Price > pivot.GG && leave.End.Price > enter.End.Price {
+		point.Side = 2
+		return point, true
+	}
+	return ChanPoint{}, false
+}
+
+// 检测最后一根已确认笔终点形成的买卖点 (最后一笔终点会随行情延伸，不作判断)
+//   - 一买/一卖：见 firstClassPoint
+//   - 二买/二卖：一买后反弹，回调低点不破一买 (卖点反之)
+//   - 三买/三卖：向上离开中枢后回调低点不回到中枢上沿 ZG 之下 (卖点反之)
+func detectChanPoints(res ChanResult, histogram []float64) []ChanPoint {
+	k := len(res.Strokes) - 2
+	if k < 0 {
+		return nil
+	}
+	last := res.Strokes[k]
+	var points []ChanPoint
+
+	if point, ok := firstClassPoint(res.Strokes, res.Pivots, histogram, k); ok {
+		points = append(points, point)
+	}
+
+	if first, ok := firstClassPoint(res.Strokes, res.Pivots, histogram, k-2); ok {
+		if first.Side == 1 && last.Direction == 2 && last.End.Price > first.Price {
+			points = append(points, ChanPoint{Side: 1, Class: 2, Stroke: k, Price: last.End.Price, Pivot: first.Pivot})
+		}
+		if first.Side == 2 && last.Direction == 1 && last.End.Price < first.Price {
+			points = append(points, ChanPoint{Side: 2, Class: 2, Stroke: k, Price: last.End.Price, Pivot: first.Pivot})
+		}
+	}
+
+	if pivot, ok := pivotBefore(res.Strokes, res.Pivots, k-1); ok && k >= 1 {
+		leave := res.Strokes[k-1]
+		if leave.Direction == 1 && last.Direction == 2 && leave.End.Price > pivot.ZG && last.End.Price > pivot.ZG {
+			points = append(points, ChanPoint{Side: 1, Class: 3, Stroke: k, Price: last.End.Price, Pivot: pivot})
+		}
+		if leave.Direction == 2 && last.Direction == 1 && leave.End.Price < pivot.ZD && last.End.Price < pivot.ZD {
+			points = append(points, ChanPoint{Side: 2, Class: 3, Stroke: k, Price: last.End.Price, Pivot: pivot})
+		}
+	}
+	return points
+}
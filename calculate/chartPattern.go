@@ -0,0 +1,257 @@
+package calculate
+
+import (
+	"IndicatorTask/binanceFapi"
+	"IndicatorTask/config"
+)
+
+// ChartPattern 几何形态
+type ChartPattern struct {
+	Name            string
+	Bias            int     // 1: 看涨, 2: 看跌, 0: 方向待突破确认
+	Upper           float64 // 上突破位 (颈线 / 上边线在最新K线处的值)
+	Lower           float64 // 下突破位
+	Height          float64 // 形态高度，用于等幅测算
+	Target          float64 // 已突破时的等幅目标价
+	Breakout        int     // 0: 未突破, 1: 向上突破, 2: 向下突破
+	VolumeConfirmed bool    // 突破K线成交量超过均量的 VolumeFactor 倍
+}
+
+// 两个波段点确定的直线
+type trendLine struct {
+	A swingPoint
+	B swingPoint
+}
+
+func (l trendLine) At(index int) float64 {
+	if l.B.Index == l.A.Index {
+		return l.B.Price
+	}
+	return l.A.Price + (l.B.Price-l.A.Price)*float64(index-l.A.Index)/float64(l.B.Index-l.A.Index)
+}
+
+// 每根K线的相对斜率 (相对价格百分比)
+func (l trendLine) Slope() float64 {
+	if l.B.Index == l.A.Index || l.A.Price == 0 {
+		return 0
+	}
+	return (l.B.Price - l.A.Price) / l.A.Price / float64(l.B.Index-l.A.Index) * 100
+}
+
+// 合并高低点为交替序列，连续同类取更极端者
+func alternateSwings(highs, lows []swingPoint) []chanFractal {
+	var merged []chanFractal
+	hi, li := 0, 0
+	for hi < len(highs) || li < len(lows) {
+		var f chanFractal
+		if li >= len(lows) || (hi < len(highs) && highs[hi].Index < lows[li].Index) {
+			f = chanFractal{Type: 1, Index: highs[hi].Index, Price: highs[hi].Price}
+			hi++
+		} else {
+			f = chanFractal{Type: 2, Index: lows[li].Index, Price: lows[li].Price}
+			li++
+		}
+		merged = append(merged, f)
+	}
+	return connectFractals(merged, 0, func(f chanFractal) int { return f.Index })
+}
+
+func toSwing(f chanFractal) swingPoint {
+	return swingPoint{Index: f.Index, Price: f.Price}
+}
+
+// 价格是否在容差范围内相等
+func nearlyEqual(a, b, tolerance float64) bool {
+	return abs(a-b) <= max(a, b)*tolerance
+}
+
+// 识别最新的几何形态，突破与放量以最后一根已收线K线判断
+func detectChartPatterns(rawKlines []binanceFapi.KLine, params config.ChartPattern) []ChartPattern {
+	if params.Window <= 0 {
+		params.Window = 3
+	}
+	if params.Lookback <= 0 {
+		params.Lookback = 120
+	}
+	if params.Tolerance <= 0 {
+		params.Tolerance = 1.0
+	}
+	if params.VolumeFactor <= 0 {
+		params.VolumeFactor = 1.5
+	}
+	klines := closedKlines(rawKlines)
+	n := len(klines)
+	if n < params.Lookback/2 || n < 30 {
+		return nil
+	}
+	start := n - params.Lookback
+	if start < 0 {
+		start = 0
+	}
+	highs, lows := findSwings(klines, params.Window, start)
+	points := alternateSwings(highs, lows)
+	m := len(points)
+	if m < 4 {
+		return nil
+	}
+	tolerance := params.Tolerance / 100
+	last := n - 1
+
+	var patterns []ChartPattern
+
+	// 双顶 / 双底 / 三重顶 / 三重底 (以最后一个同类点结尾)
+	for _, typ := range []int{1, 2} {
+		var same []chanFractal
+		var between []chanFractal
+		for i := m - 1; i >= 0 && len(same) < 3; i-- {
+			if points[i].Type == typ {
+				same = append(same, points[i])
+			} else if len(same) > 0 {
+				between = append(between, points[i])
+			}
+		}
+		if len(same) < 2 || len(between) < 1 {
+			continue
+		}
+		count := 2
+		if len(same) == 3 && len(between) >= 2 && nearlyEqual(same[0].Price, same[2].Price, tolerance) && nearlyEqual(same[1].Price, same[2].Price, tolerance) {
+			count = 3
+		}
+		if !nearlyEqual(same[0].Price, same[1].Price, tolerance) {
+			continue
+		}
+
+		peak := same[0].Price
+		neck := between[0].Price
+		for i := 1; i < count; i++ {
+			if typ == 1 {
+				peak = max(peak, same[i].Price)
+			} else {
+				peak = min(peak, same[i].Price)
+			}
+		}
+		for i := 1; i < count-1 && i < len(between); i++ {
+			if typ == 1 {
+				neck = min(neck, between[i].Price)
+			} else {
+				neck = max(neck, between[i].Price)
+			}
+		}
+
+		p := ChartPattern{Height: abs(peak - neck)}
+		if typ == 1 {
+			p.Name, p.Bias, p.Upper, p.Lower = "双顶", 2, peak, neck
+			if count == 3 {
+				p.Name = "三重顶"
+			}
+		} else {
+			p.Name, p.Bias, p.Upper, p.Lower = "双底", 1, neck, peak
+			if count == 3 {
+				p.Name = "三重底"
+			}
+		}
+		patterns = append(patterns, p)
+	}
+
+	// 头肩顶 / 头肩底：肩-颈-头-颈-肩
+	if m >= 5 {
+		s1, n1, head, n2, s2 := points[m-5], points[m-4], points[m-3], points[m-2], points[m-1]
+		neckline := trendLine{toSwing(n1), toSwing(n2)}
+		if s1.Type == 1 && head.Price > s1.Price && head.Price > s2.Price && nearlyEqual(s1.Price, s2.Price, tolerance*3) {
+			neck := neckline.At(last)
+			patterns = append(patterns, ChartPattern{Name: "头肩顶", Bias: 2, Upper: head.Price, Lower: neck, Height: head.Price - neckline.At(head.Index)})
+		}
+		if s1.Type == 2 && head.Price < s1.Price && head.Price < s2.Price && nearlyEqual(s1.Price, s2.Price, tolerance*3) {
+			neck := neckline.At(last)
+			patterns = append(patterns, ChartPattern{Name: "头肩底", Bias: 1, Upper: neck, Lower: head.Price, Height: neckline.At(head.Index) - head.Price})
+		}
+	}
+
+	// 三角形 / 楔形 / 旗形：由最近两个高点和两个低点连线
+	if len(highs) >= 2 && len(lows) >= 2 {
+		upper := trendLine{highs[len(highs)-2], highs[len(highs)-1]}
+		lower := trendLine{lows[len(lows)-2], lows[len(lows)-1]}
+		sH, sL := upper.Slope(), lower.Slope()
+		flat := params.FlatSlope
+		if flat <= 0 {
+			flat = 0.02
+		}
+		first := upper.A.Index
+		if lower.A.Index < first {
+			first = lower.A.Index
+		}
+		height := upper.At(first) - lower.At(first)
+		p := ChartPattern{Upper: upper.At(last), Lower: lower.At(last), Height: height}
+
+		switch {
+		case abs(sH) <= flat && sL > flat:
+			p.Name, p.Bias = "上升三角形", 1
+		case sH < -flat && abs(sL) <= flat:
+			p.Name, p.Bias = "下降三角形", 2
+		case sH < -flat && sL > flat:
+			p.Name = "对称三角形"
+		case sH > flat && sL > sH:
+			p.Name, p.Bias = "上升楔形", 2
+		case sL < -flat && sH < sL:
+			p.Name, p.Bias = "下降楔形", 1
+		case abs(sH-sL) <= flat && abs(sH) > flat:
+			// 平行通道，前方存在同等高度以上的反向旗杆则为旗形
+			poleStart := first - params.Lookback/4
+			if poleStart < 0 {
+				poleStart = 0
+			}
+			pole := klines[first].Close - klines[poleStart].Close
+			if sH < 0 && pole > height*2 {
+				p.Name, p.Bias, p.Height = "看涨旗形", 1, pole
+			} else if sH > 0 && -pole > height*2 {
+				p.Name, p.Bias, p.Height = "看跌旗形", 2, -pole
+			}
+		}
+		if p.Name != "" && p.Upper > p.Lower {
+			patterns = append(patterns, p)
+		}
+	}
+
+	// 突破判定与成交量确认，均量取突破K线之前的 20 根
+	var avgVol float64
+	volCount := 0
+	for i := n - 21; i < n-1; i++ {
+		if i >= 0 {
+			avgVol += klines[i].Volume
+			volCount++
+		}
+	}
+	if volCount > 0 {
+		avgVol /= float64(volCount)
+	}
+	curr, prev := klines[last].Close, klines[last-1].Close
+	for i := range patterns {
+		p := &patterns[i]
+		// 有方向的形态只认同向突破，反向突破视为形态失效
+		if curr > p.Upper && prev <= p.Upper && p.Bias != 2 {
+			p.Breakout = 1
+			p.Target = p.Upper + p.Height
+		} else if curr < p.Lower && prev >= p.Lower && p.Bias != 1 {
+			p.Breakout = 2
+			p.Target = p.Lower - p.Height
+		}
+		p.VolumeConfirmed = p.Breakout != 0 && avgVol > 0 && klines[last].Volume > avgVol*params.VolumeFactor
+	}
+	return patterns
+}
+
+// 选取最值得记录的形态：放量突破 > 突破 > 第一个识别到的形态
+func primaryChartPattern(patterns []ChartPattern) ChartPattern {
+	var res ChartPattern
+	for _, p := range patterns {
+		switch {
+		case p.Breakout != 0 && p.VolumeConfirmed:
+			return p
+		case p.Breakout != 0 && res.Breakout == 0:
+			res = p
+		case res.Name == "":
+			res = p
+		}
+	}
+	return res
+}
@@ -0,0 +1,237 @@
+package calculate
+
+import (
+	"IndicatorTask/binanceFapi"
+	"IndicatorTask/config"
+	"IndicatorTask/store"
+	"IndicatorTask/utils/logger"
+	"IndicatorTask/utils/notify"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cryptoSelect/public/database"
+)
+
+// 参与聚类的收盘价序列 (最近 Window+1 根已收盘K线)
+type clusterSeries struct {
+	Symbol   string
+	Closes   []float64
+	LastTime int64 // 最后一根已收盘K线开盘时间 (ms)，用于对齐
+}
+
+// 待发送的提醒，聚类完成后补充同簇信息再推送
+type clusterAlert struct {
+	Symbol string
+	Bias   int // 信号方向 1: 看涨, 2: 看跌, 0: 未知
+	Msg    string
+}
+
+// 聚类结果
+type symbolCluster struct {
+	ID      int
+	Members []int // clusterSeries 下标
+}
+
+func newClusterSeries(symbol string, klines []binanceFapi.KLine, window int) (clusterSeries, bool) {
+	closed := closedKlines(klines)
+	n := len(closed)
+	if n < window+1 {
+		return clusterSeries{}, false
+	}
+	return clusterSeries{
+		Symbol:   symbol,
+		Closes:   binanceFapi.ClosePrice(closed[n-window-1:]),
+		LastTime: closed[n-1].OpenTime,
+	}, true
+}
+
+// 收益率相关系数矩阵
+func correlationMatrix(series []clusterSeries) [][]float64 {
+	n := len(series)
+	corr := make([][]float64, n)
+	for i := range corr {
+		corr[i] = make([]float64, n)
+		corr[i][i] = 1
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			_, c := betaCorrelation(series[i].Closes, series[j].Closes)
+			corr[i][j], corr[j][i] = c, c
+		}
+	}
+	return corr
+}
+
+// 平均连接层次聚类：反复合并平均相关系数最高的两簇，直到最高值低于 minCorr
+func clusterSymbols(corr [][]float64, minCorr float64) []symbolCluster {
+	var clusters [][]int
+	for i := range corr {
+		clusters = append(clusters, []int{i})
+	}
+	average := func(a, b []int) float64 {
+		var sum float64
+		for _, i := range a {
+			for _, j := range b {
+				sum += corr[i][j]
+			}
+		}
+		return sum / float64(len(a)*len(b))
+	}
+	for len(clusters) > 1 {
+		bestA, bestB, best := -1, -1, minCorr
+		for a := 0; a < len(clusters); a++ {
+			for b := a + 1; b < len(clusters); b++ {
+				if avg := average(clusters[a], clusters[b]); avg >= best {
+					bestA, bestB, best = a, b, avg
+				}
+			}
+		}
+		if bestA < 0 {
+			break
+		}
+		clusters[bestA] = append(clusters[bestA], clusters[bestB]...)
+		clusters = append(clusters[:bestB], clusters[bestB+1:]...)
+	}
+
+	// 按簇大小编号，最大的簇为 1
+	sort.SliceStable(clusters, func(i, j int) bool { return len(clusters[i]) > len(clusters[j]) })
+	res := make([]symbolCluster, len(clusters))
+	for i, members := range clusters {
+		res[i] = symbolCluster{ID: i + 1, Members: members}
+	}
+	return res
+}
+
+// 计算相关性聚类并入库，之后按同簇同向信号补充提醒内容并推送
+func runClustering(cycle string, series []clusterSeries, alerts []clusterAlert, params config.Cluster) {
+	if params.MinCorrelation <= 0 {
+		params.MinCorrelation = 0.7
+	}
+
+	// 只保留与多数交易对K线时间一致的序列
+	counts := make(map[int64]int)
+	for _, s := range series {
+		counts[s.LastTime]++
+	}
+	var latest int64
+	for t, c := range counts {
+		if c > counts[latest] {
+			latest = t
+		}
+	}
+	var aligned []clusterSeries
+	for _, s := range series {
+		if s.LastTime == latest {
+			aligned = append(aligned, s)
+		}
+	}
+
+	corr := correlationMatrix(aligned)
+	clusters := clusterSymbols(corr, params.MinCorrelation)
+	clusterOf := make(map[string]int, len(aligned))
+	for _, c := range clusters {
+		for _, i := range c.Members {
+			clusterOf[aligned[i].Symbol] = c.ID
+			saveClusterRecord(cycle, aligned, corr, c, i)
+		}
+	}
+	// 本轮未参与聚类的交易对 (下架或K线时间不一致) 删除旧记录，避免残留过期的簇信息
+	if len(clusterOf) > 0 {
+		symbols := make([]string, 0, len(clusterOf))
+		for symbol := range clusterOf {
+			symbols = append(symbols, symbol)
+		}
+		if err := database.DB.Where("cycle = ? AND symbol NOT IN ?", cycle, symbols).Delete(&store.ClusterRecord{}).Error; err != nil {
+			logger.Log.Error("清理聚类记录失败", map[string]interface{}{"cycle": cycle, "err": err})
+		}
+	}
+
+	// 按 簇+方向 分组，单个交易对或未入簇的直接推送
+	groups := make(map[string][]clusterAlert)
+	var order []string
+	for _, a := range alerts {
+		id, ok := clusterOf[a.Symbol]
+		if !ok {
+			notify.Push(a.Symbol, cycle, a.Msg)
+			continue
+		}
+		key := fmt.Sprintf("%d|%d", id, a.Bias)
+		if _, exists := groups[key]; !exists {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], a)
+	}
+	for _, key := range order {
+		group := groups[key]
+		for i, a := range group {
+			var others []string
+			for j, b := range group {
+				if j != i {
+					others = append(others, strings.TrimSuffix(b.Symbol, "USDT"))
+				}
+			}
+			msg := a.Msg
+			switch {
+			case len(others) == 0:
+			case a.Bias == 0:
+				msg += fmt.Sprintf("同簇: 另有 %d 个币种触发提醒 (%s)\n", len(others), strings.Join(others, ", "))
+			case params.MergeAlerts:
+				// 合并模式下同向信号仍逐个推送给各自订阅者，只把同簇说明压缩为一行计数
+				msg += fmt.Sprintf("同簇: 共 %d 个币种同向信号\n", len(group))
+			default:
+				msg += fmt.Sprintf("同簇: 另有 %d 个币种同向信号 (%s)\n", len(others), strings.Join(others, ", "))
+			}
+			notify.Push(a.Symbol, cycle, msg)
+		}
+	}
+}
+
+// 聚类成员入库：所属簇、簇内平均相关系数及相关性最高的交易对
+func saveClusterRecord(cycle string, series []clusterSeries, corr [][]float64, c symbolCluster, i int) {
+	var avg float64
+	for _, j := range c.Members {
+		if j != i {
+			avg += corr[i][j]
+		}
+	}
+	if len(c.Members) > 1 {
+		avg /= float64(len(c.Members) - 1)
+	}
+
+	peers := make([]int, 0, len(series)-1)
+	for j := range series {
+		if j != i {
+			peers = append(peers, j)
+		}
+	}
+	sort.Slice(peers, func(a, b int) bool { return corr[i][peers[a]] > corr[i][peers[b]] })
+	if len(peers) > 5 {
+		peers = peers[:5]
+	}
+	top := make([][]interface{}, 0, len(peers))
+	for _, j := range peers {
+		top = append(top, []interface{}{series[j].Symbol, corr[i][j]})
+	}
+	peersJSON, _ := json.Marshal(top)
+
+	symbol := series[i].Symbol
+	updates := map[string]interface{}{
+		"cluster_id":      c.ID,
+		"size":            len(c.Members),
+		"avg_correlation": avg,
+		"peers":           string(peersJSON),
+	}
+	result := database.DB.Model(&store.ClusterRecord{}).
+		Where("symbol = ? AND cycle = ?", symbol, cycle).
+		Updates(updates)
+	if result.Error != nil || result.RowsAffected != 0 {
+		return
+	}
+
+	// 首次写入
+	updates["symbol"] = symbol
+	updates["cycle"] = cycle
+	_ = database.DB.Model(&store.ClusterRecord{}).Create(updates).Error
+}
@@ -0,0 +1,153 @@
+package calculate
+
+import (
+	"IndicatorTask/config"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 单个周期的最新状态，供其他周期做多周期共振判断
+type cycleState struct {
+	Trend     int // 0: 无, 1: 多头, 2: 空头 (收盘相对 EMA 及 EMA 斜率)
+	RsiZone   int // 0: 中性/超买超卖, 1: 偏多 (50 ~ 超买线), 2: 偏空 (超卖线 ~ 50)
+	Macd      int // 0: 无, 1: DIF 在 DEA 上方, 2: DIF 在 DEA 下方
+	UpdatedAt time.Time
+}
+
+var (
+	cycleStates   = make(map[string]cycleState)
+	cycleStatesMu sync.RWMutex
+)
+
+// ConfluenceResult 多周期共振评分
+type ConfluenceResult struct {
+	Bias    int      // 信号方向 1: 看涨, 2: 看跌
+	Score   int      // 各高级别周期各项 同向 +1 / 反向 -1 之和
+	Max     int      // 满分 (参与评分的高级别周期数 * 3)
+	Details []string // 各高级别周期状态，如 4h:多头/RSI偏多/MACD多
+}
+
+// 得分百分比，无高级别周期数据时为 0
+func (c ConfluenceResult) Percent() float64 {
+	if c.Max == 0 {
+		return 0
+	}
+	return float64(c.Score) / float64(c.Max) * 100
+}
+
+// 根据收盘价、RSI 与 MACD 生成周期状态
+func buildCycleState(closes, macd, signalLine []float64, rsi float64, params config.Confluence) cycleState {
+	if params.TrendPeriod <= 0 {
+		params.TrendPeriod = 50
+	}
+	state := cycleState{UpdatedAt: time.Now()}
+	n := len(closes)
+	if n < 2 {
+		return state
+	}
+	ema := calculateEMA(closes, params.TrendPeriod)
+	if closes[n-1] > ema[n-1] && ema[n-1] >= ema[n-2] {
+		state.Trend = 1
+	} else if closes[n-1] < ema[n-1] && ema[n-1] <= ema[n-2] {
+		state.Trend = 2
+	}
+
+	top, low := float64(config.Cfg.Benchmark.Rsi.Top), float64(config.Cfg.Benchmark.Rsi.Low)
+	if top <= 0 {
+		top = 70
+	}
+	if low <= 0 {
+		low = 30
+	}
+	if rsi >= 50 && rsi < top {
+		state.RsiZone = 1
+	} else if rsi < 50 && rsi > low {
+		state.RsiZone = 2
+	}
+
+	if len(macd) == n && len(signalLine) == n {
+		if macd[n-1] > signalLine[n-1] {
+			state.Macd = 1
+		} else if macd[n-1] < signalLine[n-1] {
+			state.Macd = 2
+		}
+	}
+	return state
+}
+
+func updateCycleState(symbol, cycle string, state cycleState) {
+	cycleStatesMu.Lock()
+	cycleStates[symbol+"|"+cycle] = state
+	cycleStatesMu.Unlock()
+}
+
+// 同向 +1，反向 -1，中性 0
+func alignScore(bias, direction int) int {
+	switch {
+	case direction == 0:
+		return 0
+	case direction == bias:
+		return 1
+	}
+	return -1
+}
+
+func directionFmt(direction int, bull, bear string) string {
+	switch direction {
+	case 1:
+		return bull
+	case 2:
+		return bear
+	}
+	return "中性"
+}
+
+// 信号方向：MACD 交叉 > SMC 结构突破 > Supertrend 翻转 > 本周期趋势
+func signalBias(crossType int, ind *indicatorResult, state cycleState) int {
+	switch {
+	case crossType == 1 || crossType == 2:
+		return 1
+	case crossType == 3 || crossType == 4:
+		return 2
+	case strings.HasSuffix(ind.Smc.Signal, "Bullish"):
+		return 1
+	case strings.HasSuffix(ind.Smc.Signal, "Bearish"):
+		return 2
+	case ind.Supertrend.Flip != 0:
+		return ind.Supertrend.Flip
+	}
+	return state.Trend
+}
+
+// 查找同一交易对在更高级别周期的最新状态并评分，超过两个周期时长未更新的状态视为过期
+func calculateConfluence(symbol, cycle string, bias int) ConfluenceResult {
+	res := ConfluenceResult{Bias: bias}
+	if bias == 0 {
+		return res
+	}
+	current, err := CycleDurationFmt(cycle)
+	if err != nil {
+		return res
+	}
+
+	cycleStatesMu.RLock()
+	defer cycleStatesMu.RUnlock()
+	for _, c := range config.Cfg.Cycles {
+		// 无效周期启动时已跳过，不参与共振
+		duration, err := CycleDurationFmt(c.Cycle)
+		if err != nil || duration <= current {
+			continue
+		}
+		state, ok := cycleStates[symbol+"|"+c.Cycle]
+		if !ok || time.Since(state.UpdatedAt) > 2*duration {
+			continue
+		}
+		res.Score += alignScore(bias, state.Trend) + alignScore(bias, state.RsiZone) + alignScore(bias, state.Macd)
+		res.Max += 3
+		res.Details = append(res.Details, fmt.Sprintf("%s:%s/RSI%s/MACD%s", c.Cycle,
+			directionFmt(state.Trend, "多头", "空头"), directionFmt(state.RsiZone, "偏多", "偏空"), directionFmt(state.Macd, "多", "空")))
+	}
+	return res
+}
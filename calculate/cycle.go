@@ -0,0 +1,285 @@
+package calculate
+
+import (
+	"IndicatorTask/binanceFapi"
+	"IndicatorTask/config"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Binance 合约支持的K线周期
+var binanceIntervals = []struct {
+	Name     string
+	Duration time.Duration
+}{
+	{"1m", time.Minute},
+	{"3m", 3 * time.Minute},
+	{"5m", 5 * time.Minute},
+	{"15m", 15 * time.Minute},
+	{"30m", 30 * time.Minute},
+	{"1h", time.Hour},
+	{"2h", 2 * time.Hour},
+	{"4h", 4 * time.Hour},
+	{"6h", 6 * time.Hour},
+	{"8h", 8 * time.Hour},
+	{"12h", 12 * time.Hour},
+	{"1d", 24 * time.Hour},
+	{"3d", 3 * 24 * time.Hour},
+	{"1w", 7 * 24 * time.Hour},
+	{"1M", 30 * 24 * time.Hour},
+}
+
+// 单次请求最多返回的K线数
+const maxKlinesPerRequest = 1500
+
+// 自定义周期每个交易对每轮最多分页请求次数，超过时需配置更大的 Base 或减少 Klines
+const maxKlinePages = 4
+
+// 周线从周一 00:00 UTC 开始 (1970-01-05)
+const weekAnchor = 4 * 24 * time.Hour
+
+// 周期定义：Binance 原生周期直接拉取，其余由基础周期K线重采样生成
+type cycleSpec struct {
+	Name     string
+	Duration time.Duration
+	Offset   time.Duration // 时区偏移，如 1d@UTC+8 为 8h
+	Anchor   time.Duration // 周期起点相对 Unix 纪元的偏移，周线为周一
+	Base     string        // 拉取的 Binance 周期
+	Multiple int           // 每根K线包含的基础K线数，1 表示原生周期
+}
+
+// 校验周期配置，启动时过滤无效周期及分页请求过多的自定义周期
+func ValidateCycle(cycle string) error {
+	spec, err := parseCycle(cycle)
+	if err != nil {
+		return err
+	}
+	return spec.checkPages(config.Cfg.Benchmark.Klines)
+}
+
+// 重采样 limit 根K线所需的分页请求次数不能超过 maxKlinePages
+func (c cycleSpec) checkPages(limit int) error {
+	if c.Multiple <= 1 {
+		return nil
+	}
+	need := (limit + 1) * c.Multiple
+	pages := (need + maxKlinesPerRequest - 1) / maxKlinesPerRequest
+	if pages > maxKlinePages {
+		return fmt.Errorf("周期 %s 由 %s 重采样需 %d 根基础K线 (%d 次请求)，超过上限 %d 次", c.Name, c.Base, need, pages, maxKlinePages)
+	}
+	return nil
+}
+
+// 解析周期：<数量><单位>[@UTC±H[:MM]]，单位 m/h/d/w，月线仅支持 1M
+func parseCycle(cycle string) (cycleSpec, error) {
+	spec := cycleSpec{Name: cycle}
+	interval, zone, hasZone := strings.Cut(strings.TrimSpace(cycle), "@")
+	if hasZone {
+		offset, err := parseUtcOffset(zone)
+		if err != nil {
+			return spec, err
+		}
+		spec.Offset = offset
+	}
+
+	if interval == "1M" && spec.Offset == 0 {
+		spec.Duration, spec.Base, spec.Multiple = 30*24*time.Hour, interval, 1
+		return spec, nil
+	}
+	if len(interval) < 2 {
+		return spec, fmt.Errorf("无效周期: %s", cycle)
+	}
+	count, err := strconv.Atoi(interval[:len(interval)-1])
+	if err != nil || count <= 0 {
+		return spec, fmt.Errorf("无效周期: %s", cycle)
+	}
+	switch interval[len(interval)-1] {
+	case 'm':
+		spec.Duration = time.Duration(count) * time.Minute
+	case 'h':
+		spec.Duration = time.Duration(count) * time.Hour
+	case 'd':
+		spec.Duration = time.Duration(count) * 24 * time.Hour
+	case 'w':
+		spec.Duration = time.Duration(count) * 7 * 24 * time.Hour
+		spec.Anchor = weekAnchor
+	default:
+		return spec, fmt.Errorf("不支持的周期单位: %s", cycle)
+	}
+
+	// 原生周期直接拉取
+	if spec.Offset == 0 {
+		for _, b := range binanceIntervals {
+			if b.Name == interval {
+				spec.Base, spec.Multiple = b.Name, 1
+				return spec, nil
+			}
+		}
+	}
+
+	// 自定义周期：使用配置的基础周期，或自动选择能整除且边界对齐的最大 Binance 周期
+	base := cycleBase(cycle)
+	for i := len(binanceIntervals) - 1; i >= 0; i-- {
+		b := binanceIntervals[i]
+		if b.Name == "1M" || (base != "" && b.Name != base) {
+			continue
+		}
+		if b.Duration >= spec.Duration || spec.Duration%b.Duration != 0 {
+			continue
+		}
+		var anchor time.Duration
+		if b.Name == "1w" {
+			anchor = weekAnchor
+		}
+		if (spec.Offset-spec.Anchor+anchor)%b.Duration != 0 {
+			continue
+		}
+		spec.Base, spec.Multiple = b.Name, int(spec.Duration/b.Duration)
+		return spec, nil
+	}
+	if base != "" {
+		return spec, fmt.Errorf("基础周期 %s 无法重采样为 %s", base, cycle)
+	}
+	return spec, fmt.Errorf("没有可重采样为 %s 的基础周期", cycle)
+}
+
+// 解析 UTC±H[:MM] 时区偏移
+func parseUtcOffset(zone string) (time.Duration, error) {
+	zone = strings.ToUpper(strings.TrimSpace(zone))
+	if !strings.HasPrefix(zone, "UTC") {
+		return 0, fmt.Errorf("无效时区: %s", zone)
+	}
+	rest := zone[3:]
+	if rest == "" {
+		return 0, nil
+	}
+	sign := time.Duration(1)
+	switch rest[0] {
+	case '+':
+	case '-':
+		sign = -1
+	default:
+		return 0, fmt.Errorf("无效时区: %s", zone)
+	}
+	hourStr, minuteStr, hasMinute := strings.Cut(rest[1:], ":")
+	hours, err := strconv.Atoi(hourStr)
+	if err != nil || hours < 0 || hours > 14 {
+		return 0, fmt.Errorf("无效时区: %s", zone)
+	}
+	var minutes int
+	if hasMinute {
+		if minutes, err = strconv.Atoi(minuteStr); err != nil || minutes < 0 || minutes >= 60 {
+			return 0, fmt.Errorf("无效时区: %s", zone)
+		}
+	}
+	return sign * (time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute), nil
+}
+
+// 周期配置的基础周期
+func cycleBase(cycle string) string {
+	if config.Cfg == nil {
+		return ""
+	}
+	for _, c := range config.Cfg.Cycles {
+		if c.Cycle == cycle {
+			return c.Base
+		}
+	}
+	return ""
+}
+
+// 时间 (ms) 所在K线的开盘时间
+func (c cycleSpec) bucketStart(t int64) int64 {
+	shift := (c.Offset - c.Anchor).Milliseconds()
+	d := c.Duration.Milliseconds()
+	start := (t + shift) / d * d
+	if t+shift < 0 && (t+shift)%d != 0 {
+		start -= d
+	}
+	return start - shift
+}
+
+// 下一根K线的开盘时间，月线按自然月
+func (c cycleSpec) nextStart(now time.Time) time.Time {
+	if c.Name == "1M" {
+		utc := now.UTC()
+		return time.Date(utc.Year(), utc.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.UnixMilli(c.bucketStart(now.UnixMilli())).Add(c.Duration)
+}
+
+// 获取周期K线
+func getCycleKlines(symbol, cycle string) ([]binanceFapi.KLine, error) {
+	return getCycleKlinesLimit(symbol, cycle, config.Cfg.Benchmark.Klines)
+}
+
+// 获取指定数量的周期K线，自定义周期分页拉取基础周期K线后重采样
+func getCycleKlinesLimit(symbol, cycle string, limit int) ([]binanceFapi.KLine, error) {
+	spec, err := parseCycle(cycle)
+	if err != nil {
+		return nil, err
+	}
+	if spec.Multiple == 1 {
+		return binanceFapi.GetContractKlinesLimit(symbol, spec.Base, limit)
+	}
+	if err := spec.checkPages(limit); err != nil {
+		return nil, err
+	}
+
+	// 多拉一组基础K线，首根不完整的K线会被丢弃
+	need := (limit + 1) * spec.Multiple
+	var base []binanceFapi.KLine
+	var endTime int64
+	for len(base) < need {
+		size := need - len(base)
+		if size > maxKlinesPerRequest {
+			size = maxKlinesPerRequest
+		}
+		klines, err := binanceFapi.GetContractKlinesBefore(symbol, spec.Base, size, endTime)
+		if err != nil {
+			return nil, err
+		}
+		base = append(klines, base...)
+		// 上市时间不足，已无更早K线
+		if len(klines) < size {
+			break
+		}
+		endTime = klines[0].OpenTime - 1
+	}
+
+	res := resampleKlines(base, spec)
+	if len(res) > limit {
+		res = res[len(res)-limit:]
+	}
+	return res, nil
+}
+
+// 将基础周期K线按周期边界合并，丢弃开头不完整的K线；最新一根与原生周期一样可能未收线
+func resampleKlines(klines []binanceFapi.KLine, spec cycleSpec) []binanceFapi.KLine {
+	first := 0
+	for first < len(klines) && spec.bucketStart(klines[first].OpenTime) != klines[first].OpenTime {
+		first++
+	}
+
+	var res []binanceFapi.KLine
+	for _, k := range klines[first:] {
+		start := spec.bucketStart(k.OpenTime)
+		if n := len(res); n > 0 && res[n-1].OpenTime == start {
+			bar := &res[n-1]
+			bar.High, bar.Low = max(bar.High, k.High), min(bar.Low, k.Low)
+			bar.Close = k.Close
+			bar.Volume += k.Volume
+			bar.QuoteVolume += k.QuoteVolume
+			bar.NumTrades += k.NumTrades
+			bar.TakerBuyVolume += k.TakerBuyVolume
+			bar.TakerBuyQuoteVol += k.TakerBuyQuoteVol
+			continue
+		}
+		bar := k
+		bar.OpenTime, bar.CloseTime = start, start+spec.Duration.Milliseconds()-1
+		res = append(res, bar)
+	}
+	return res
+}
@@ -0,0 +1,185 @@
+package calculate
+
+import (
+	"testing"
+	"time"
+
+	"IndicatorTask/binanceFapi"
+)
+
+func ms(year int, month time.Month, day, hour, minute int) int64 {
+	return time.Date(year, month, day, hour, minute, 0, 0, time.UTC).UnixMilli()
+}
+
+func TestParseCycle(t *testing.T) {
+	tests := []struct {
+		cycle    string
+		duration time.Duration
+		offset   time.Duration
+		base     string
+		multiple int
+		wantErr  bool
+	}{
+		{cycle: "1m", duration: time.Minute, base: "1m", multiple: 1},
+		{cycle: "3m", duration: 3 * time.Minute, base: "3m", multiple: 1},
+		{cycle: "2h", duration: 2 * time.Hour, base: "2h", multiple: 1},
+		{cycle: "12h", duration: 12 * time.Hour, base: "12h", multiple: 1},
+		{cycle: "3d", duration: 72 * time.Hour, base: "3d", multiple: 1},
+		{cycle: "1w", duration: 7 * 24 * time.Hour, base: "1w", multiple: 1},
+		{cycle: "1M", duration: 30 * 24 * time.Hour, base: "1M", multiple: 1},
+		{cycle: "10m", duration: 10 * time.Minute, base: "5m", multiple: 2},
+		{cycle: "7m", duration: 7 * time.Minute, base: "1m", multiple: 7},
+		{cycle: "2d", duration: 48 * time.Hour, base: "1d", multiple: 2},
+		{cycle: "2w", duration: 14 * 24 * time.Hour, base: "1w", multiple: 2},
+		{cycle: "1d@UTC", duration: 24 * time.Hour, base: "1d", multiple: 1},
+		{cycle: "1d@UTC+8", duration: 24 * time.Hour, offset: 8 * time.Hour, base: "8h", multiple: 3},
+		{cycle: "1d@UTC-5", duration: 24 * time.Hour, offset: -5 * time.Hour, base: "1h", multiple: 24},
+		{cycle: "1d@UTC+5:30", duration: 24 * time.Hour, offset: 330 * time.Minute, base: "30m", multiple: 48},
+		{cycle: "1w@UTC+8", duration: 7 * 24 * time.Hour, offset: 8 * time.Hour, base: "8h", multiple: 21},
+		{cycle: "", wantErr: true},
+		{cycle: "x", wantErr: true},
+		{cycle: "0h", wantErr: true},
+		{cycle: "2M", wantErr: true},
+		{cycle: "1M@UTC+8", wantErr: true},
+		{cycle: "5s", wantErr: true},
+		{cycle: "1d@GMT+8", wantErr: true},
+		{cycle: "1d@UTC+15", wantErr: true},
+		{cycle: "1d@UTC+8:60", wantErr: true},
+	}
+	for _, tt := range tests {
+		spec, err := parseCycle(tt.cycle)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseCycle(%q) expected error, got %+v", tt.cycle, spec)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseCycle(%q) unexpected error: %v", tt.cycle, err)
+			continue
+		}
+		if spec.Duration != tt.duration || spec.Offset != tt.offset || spec.Base != tt.base || spec.Multiple != tt.multiple {
+			t.Errorf("parseCycle(%q) = %v/%v/%s x%d, want %v/%v/%s x%d", tt.cycle,
+				spec.Duration, spec.Offset, spec.Base, spec.Multiple, tt.duration, tt.offset, tt.base, tt.multiple)
+		}
+	}
+}
+
+func TestCheckPages(t *testing.T) {
+	tests := []struct {
+		cycle   string
+		limit   int
+		wantErr bool
+	}{
+		{cycle: "4h", limit: 1500},
+		{cycle: "10m", limit: 500},
+		{cycle: "1d@UTC+8", limit: 500},
+		{cycle: "1w@UTC+8", limit: 500, wantErr: true},
+		{cycle: "1w@UTC+8", limit: 200},
+	}
+	for _, tt := range tests {
+		spec, err := parseCycle(tt.cycle)
+		if err != nil {
+			t.Fatalf("parseCycle(%q): %v", tt.cycle, err)
+		}
+		if err := spec.checkPages(tt.limit); (err != nil) != tt.wantErr {
+			t.Errorf("checkPages(%q, %d) error = %v, wantErr %v", tt.cycle, tt.limit, err, tt.wantErr)
+		}
+	}
+}
+
+func TestBucketStart(t *testing.T) {
+	tests := []struct {
+		cycle string
+		at    int64
+		want  int64
+	}{
+		{cycle: "1h", at: ms(2026, 10, 19, 17, 45), want: ms(2026, 10, 19, 17, 0)},
+		{cycle: "10m", at: ms(2026, 10, 19, 17, 59), want: ms(2026, 10, 19, 17, 50)},
+		{cycle: "1d", at: ms(2026, 10, 19, 23, 59), want: ms(2026, 10, 19, 0, 0)},
+		// 东八区日线从 UTC 16:00 开始
+		{cycle: "1d@UTC+8", at: ms(2026, 10, 19, 15, 59), want: ms(2026, 10, 18, 16, 0)},
+		{cycle: "1d@UTC+8", at: ms(2026, 10, 19, 16, 0), want: ms(2026, 10, 19, 16, 0)},
+		{cycle: "1d@UTC-5", at: ms(2026, 10, 19, 4, 0), want: ms(2026, 10, 18, 5, 0)},
+		// 2026-10-19 为周一
+		{cycle: "1w", at: ms(2026, 10, 25, 23, 59), want: ms(2026, 10, 19, 0, 0)},
+		{cycle: "1w", at: ms(2026, 10, 26, 0, 0), want: ms(2026, 10, 26, 0, 0)},
+		{cycle: "1w@UTC+8", at: ms(2026, 10, 19, 8, 0), want: ms(2026, 10, 18, 16, 0)},
+		{cycle: "3d", at: ms(1970, 1, 4, 0, 0), want: ms(1970, 1, 4, 0, 0)},
+		{cycle: "3d", at: ms(1970, 1, 3, 23, 59), want: ms(1970, 1, 1, 0, 0)},
+	}
+	for _, tt := range tests {
+		spec, err := parseCycle(tt.cycle)
+		if err != nil {
+			t.Fatalf("parseCycle(%q): %v", tt.cycle, err)
+		}
+		if got := spec.bucketStart(tt.at); got != tt.want {
+			t.Errorf("%s bucketStart(%s) = %s, want %s", tt.cycle,
+				time.UnixMilli(tt.at).UTC(), time.UnixMilli(got).UTC(), time.UnixMilli(tt.want).UTC())
+		}
+	}
+}
+
+func TestNextStart(t *testing.T) {
+	now := time.Date(2026, 10, 19, 17, 0, 0, 0, time.UTC)
+	tests := []struct {
+		cycle string
+		want  time.Time
+	}{
+		{cycle: "4h", want: time.Date(2026, 10, 19, 20, 0, 0, 0, time.UTC)},
+		{cycle: "1d@UTC+8", want: time.Date(2026, 10, 20, 16, 0, 0, 0, time.UTC)},
+		{cycle: "1w", want: time.Date(2026, 10, 26, 0, 0, 0, 0, time.UTC)},
+		{cycle: "1M", want: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		spec, err := parseCycle(tt.cycle)
+		if err != nil {
+			t.Fatalf("parseCycle(%q): %v", tt.cycle, err)
+		}
+		if got := spec.nextStart(now); !got.Equal(tt.want) {
+			t.Errorf("%s nextStart = %s, want %s", tt.cycle, got.UTC(), tt.want)
+		}
+	}
+}
+
+func TestResampleKlines(t *testing.T) {
+	spec, err := parseCycle("1d@UTC+8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 8h 基础K线从 UTC 08:00 开始，第一根不完整的东八区日线应被丢弃
+	const step = int64(8 * time.Hour / time.Millisecond)
+	start := ms(2026, 1, 1, 8, 0)
+	var base []binanceFapi.KLine
+	for i := 0; i < 8; i++ {
+		p := float64(i)
+		base = append(base, binanceFapi.KLine{
+			OpenTime: start + int64(i)*step, CloseTime: start + int64(i+1)*step - 1,
+			Open: p, High: p + 1, Low: p - 1, Close: p + 0.5,
+			Volume: 1, QuoteVolume: 10, NumTrades: 2, TakerBuyVolume: 0.5, TakerBuyQuoteVol: 5,
+		})
+	}
+
+	want := []binanceFapi.KLine{
+		{OpenTime: ms(2026, 1, 1, 16, 0), CloseTime: ms(2026, 1, 2, 16, 0) - 1, Open: 1, High: 4, Low: 0, Close: 3.5,
+			Volume: 3, QuoteVolume: 30, NumTrades: 6, TakerBuyVolume: 1.5, TakerBuyQuoteVol: 15},
+		{OpenTime: ms(2026, 1, 2, 16, 0), CloseTime: ms(2026, 1, 3, 16, 0) - 1, Open: 4, High: 7, Low: 3, Close: 6.5,
+			Volume: 3, QuoteVolume: 30, NumTrades: 6, TakerBuyVolume: 1.5, TakerBuyQuoteVol: 15},
+		// 最新一根未走完
+		{OpenTime: ms(2026, 1, 3, 16, 0), CloseTime: ms(2026, 1, 4, 16, 0) - 1, Open: 7, High: 8, Low: 6, Close: 7.5,
+			Volume: 1, QuoteVolume: 10, NumTrades: 2, TakerBuyVolume: 0.5, TakerBuyQuoteVol: 5},
+	}
+	got := resampleKlines(base, spec)
+	if len(got) != len(want) {
+		t.Fatalf("resampleKlines returned %d klines, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("kline %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if got := resampleKlines(nil, spec); len(got) != 0 {
+		t.Errorf("resampleKlines(nil) = %d klines, want 0", len(got))
+	}
+}
@@ -17,7 +17,7 @@ func formatWithWan(val float64) string {
 }
 
 // 统一消息格式化
-func alertMsgFmt(info *binanceFapi.SymbolInfo, cycle string) string {
+func alertMsgFmt(info *binanceFapi.SymbolInfo, cycle string, ind *indicatorResult) string {
 	var builder strings.Builder
 
 	// 1. 标题
@@ -36,6 +36,16 @@ func alertMsgFmt(info *binanceFapi.SymbolInfo, cycle string) string {
 	// 2. 基础信息
 	builder.WriteString(fmt.Sprintf("价格: %.4f(%.2f%%)\n", info.Price, info.Change))
 
+	// 所属板块
+	if sectors := config.SymbolSectors(info.Symbol); len(sectors) > 0 {
+		builder.WriteString(fmt.Sprintf("板块: %s\n", strings.Join(sectors, ", ")))
+	}
+
+	// 多周期共振
+	if ind.Confluence.Max > 0 {
+		builder.WriteString(confluenceMsgFmt(ind.Confluence))
+	}
+
 	// 3. 信号信息 (动态包含)
 	// MACD 交叉
 	// MACD 交叉
@@ -51,7 +61,12 @@ func alertMsgFmt(info *binanceFapi.SymbolInfo, cycle string) string {
 		case 4:
 			crossStr = "死叉0轴下"
 		}
-		builder.WriteString(fmt.Sprintf("MACD: %s\n", crossStr))
+		builder.WriteString(fmt.Sprintf("MACD: %s%s\n", crossStr, transformFmt(ind.Transform)))
+	}
+
+	// MACD 柱状图
+	if ind.MacdHist.Sign > 0 {
+		builder.WriteString(macdHistMsgFmt(ind.MacdHist))
 	}
 
 	// 缠论分型
@@ -65,6 +80,41 @@ func alertMsgFmt(info *binanceFapi.SymbolInfo, cycle string) string {
 		builder.WriteString(fmt.Sprintf("形态: %s\n", shapeStr))
 	}
 
+	// K线形态 (仅关键位置)
+	if signal := candleSignalFmt(ind.Candles); signal != "" {
+		builder.WriteString(fmt.Sprintf("K线: %s\n", signal))
+	}
+
+	// 枢轴点
+	if len(ind.Pivot.Sets) > 0 {
+		builder.WriteString(pivotMsgFmt(ind.Pivot))
+	}
+
+	// 相对强弱
+	for _, rs := range ind.Relative {
+		builder.WriteString(relativeStrengthMsgFmt(rs))
+	}
+
+	// 斐波那契回撤
+	if ind.Fib.Direction != 0 {
+		builder.WriteString(fibMsgFmt(ind.Fib))
+	}
+
+	// 几何形态 (仅显示最主要的一个)
+	if chart := primaryChartPattern(ind.Charts); chart.Name != "" {
+		builder.WriteString(chartPatternMsgFmt(chart))
+	}
+
+	// SMC
+	if ind.Smc.Trend > 0 || ind.Smc.Signal != "" {
+		builder.WriteString(smcMsgFmt(ind.Smc))
+	}
+
+	// 缠论中枢
+	if len(ind.Chan.Pivots) > 0 {
+		builder.WriteString(chanMsgFmt(ind.Chan))
+	}
+
 	// RSI 状态
 	rsiStatus := ""
 	if info.Rsi >= float64(config.Cfg.Benchmark.Rsi.Top) {
@@ -74,9 +124,46 @@ func alertMsgFmt(info *binanceFapi.SymbolInfo, cycle string) string {
 	}
 	builder.WriteString(fmt.Sprintf("RSI: %.2f%s\n", info.Rsi, rsiStatus))
 
+	// 震荡指标
+	builder.WriteString(oscillatorMsgFmt(ind.Oscillator))
+
+	// 一目均衡表
+	if ind.Ichimoku.CloudPosition > 0 {
+		builder.WriteString(ichimokuMsgFmt(ind.Ichimoku))
+	}
+
+	// 锚定 VWAP
+	if vwapDay, ok := ind.Vwap.Band(VwapAnchorDay); ok {
+		builder.WriteString(fmt.Sprintf("VWAP: 日 %.4f ±%.4f", vwapDay.Vwap, vwapDay.Std))
+		if signal := vwapSignalFmt(ind.Vwap); signal != "" {
+			builder.WriteString(" | " + signal)
+		}
+		builder.WriteString("\n")
+	} else if signal := vwapSignalFmt(ind.Vwap); signal != "" {
+		builder.WriteString(fmt.Sprintf("VWAP: %s\n", signal))
+	}
+
+	// 超级趋势 / SAR
+	if ind.Supertrend.Trend > 0 {
+		builder.WriteString(trendStopMsgFmt("超级趋势", ind.Supertrend))
+	}
+	if ind.Sar.Trend > 0 {
+		builder.WriteString(trendStopMsgFmt("SAR", ind.Sar))
+	}
+
 	// 成交信息
 	builder.WriteString(fmt.Sprintf("成交: %s (%.2f%%)\n", formatWithWan(info.Volume), info.TakerBuyRatio))
 
+	// 成交量分布
+	if len(ind.Profile.Profiles) > 0 {
+		builder.WriteString(volumeProfileMsgFmt(ind.Profile))
+	}
+
+	// 量能指标
+	if ind.VolumeFlow.Enabled {
+		builder.WriteString(volumeFlowMsgFmt(ind.VolumeFlow))
+	}
+
 	// 量价分析 (仅在不正常时显示)
 	vp := info.VpSignal
 	if vp != "" {
@@ -97,30 +184,442 @@ func alertMsgFmt(info *binanceFapi.SymbolInfo, cycle string) string {
 	return builder.String()
 }
 
-// 判断使用小时周期还是分钟周期
-func CycleDurationFmt(cycle string) time.Duration {
-	var duration time.Duration
-	switch cycle {
-	case "5m":
-		duration = 5 * time.Minute
-	case "15m":
-		duration = 15 * time.Minute
-	case "30m":
-		duration = 30 * time.Minute
-	case "1h":
-		duration = 1 * time.Hour
-	case "4h":
-		duration = 4 * time.Hour
-	case "1d":
-		duration = 24 * time.Hour
-	case "1w":
-		duration = 7 * 24 * time.Hour
-	case "1M":
-		duration = 30 * 24 * time.Hour
-	default:
-		duration = 30 * time.Minute
-	}
-	return duration
+// 一目均衡表状态描述
+func ichimokuMsgFmt(res IchimokuResult) string {
+	var parts []string
+	switch res.CloudPosition {
+	case 1:
+		parts = append(parts, "云上")
+	case 2:
+		parts = append(parts, "云中")
+	case 3:
+		parts = append(parts, "云下")
+	}
+	switch res.TkCross {
+	case 1:
+		parts = append(parts, "TK金叉")
+	case 2:
+		parts = append(parts, "TK死叉")
+	}
+	switch res.CloudTwist {
+	case 1:
+		parts = append(parts, "云转多")
+	case 2:
+		parts = append(parts, "云转空")
+	}
+	switch res.ChikouConfirm {
+	case 1:
+		parts = append(parts, "迟行线确认多头")
+	case 2:
+		parts = append(parts, "迟行线确认空头")
+	}
+	return fmt.Sprintf("一目: %s (基准线 %.4f)\n", strings.Join(parts, " "), res.Kijun)
+}
+
+// VWAP 信号描述，无信号返回空
+func vwapSignalFmt(res VwapResult) string {
+	var parts []string
+	for _, b := range res.Bands {
+		switch b.Signal {
+		case 1:
+			parts = append(parts, fmt.Sprintf("收复%sVWAP", b.Anchor))
+		case 2:
+			parts = append(parts, fmt.Sprintf("跌破%sVWAP", b.Anchor))
+		}
+		switch b.BandTouch {
+		case 1:
+			parts = append(parts, fmt.Sprintf("触及%sVWAP上轨", b.Anchor))
+		case 2:
+			parts = append(parts, fmt.Sprintf("触及%sVWAP下轨", b.Anchor))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// 趋势跟踪指标描述，翻转时突出显示止损位
+func trendStopMsgFmt(name string, res TrendStopResult) string {
+	trendStr := "多头"
+	if res.Trend == 2 {
+		trendStr = "空头"
+	}
+	switch res.Flip {
+	case 1:
+		return fmt.Sprintf("%s: 翻多 止损 %.4f\n", name, res.Stop)
+	case 2:
+		return fmt.Sprintf("%s: 翻空 止损 %.4f\n", name, res.Stop)
+	}
+	return fmt.Sprintf("%s: %s 止损 %.4f\n", name, trendStr, res.Stop)
+}
+
+// 量能指标描述
+func volumeFlowMsgFmt(res VolumeFlowResult) string {
+	mfiStatus := ""
+	switch res.MfiZone {
+	case 1:
+		mfiStatus = " (超买)"
+	case 2:
+		mfiStatus = " (超卖)"
+	}
+	msg := fmt.Sprintf("MFI: %.2f%s CMF: %.3f", res.Mfi, mfiStatus, res.Cmf)
+	switch res.ObvDivergence {
+	case 1:
+		msg += " OBV底背离"
+	case 2:
+		msg += " OBV顶背离"
+	}
+	return msg + "\n"
+}
+
+// 震荡指标描述，仅输出已启用 (有值) 的指标
+func oscillatorMsgFmt(res OscillatorResult) string {
+	status := func(line OscillatorLine) string {
+		var parts []string
+		switch line.Cross {
+		case 1:
+			parts = append(parts, "金叉")
+		case 2:
+			parts = append(parts, "死叉")
+		}
+		switch line.Zone {
+		case 1:
+			parts = append(parts, "超买")
+		case 2:
+			parts = append(parts, "超卖")
+		}
+		if len(parts) == 0 {
+			return ""
+		}
+		return " (" + strings.Join(parts, " ") + ")"
+	}
+
+	var builder strings.Builder
+	if res.Kdj.Enabled {
+		builder.WriteString(fmt.Sprintf("KDJ: %.2f/%.2f/%.2f%s\n", res.Kdj.K, res.Kdj.D, res.Kdj.J, status(res.Kdj)))
+	}
+	if res.Stoch.Enabled {
+		builder.WriteString(fmt.Sprintf("Stoch: %.2f/%.2f%s\n", res.Stoch.K, res.Stoch.D, status(res.Stoch)))
+	}
+	if res.StochRsi.Enabled {
+		builder.WriteString(fmt.Sprintf("StochRSI: %.2f/%.2f%s\n", res.StochRsi.K, res.StochRsi.D, status(res.StochRsi)))
+	}
+	if res.Cci.Enabled {
+		builder.WriteString(fmt.Sprintf("CCI: %.2f%s\n", res.Cci.K, status(res.Cci)))
+	}
+	if res.Wr.Enabled {
+		builder.WriteString(fmt.Sprintf("WR: %.2f%s\n", res.Wr.K, status(res.Wr)))
+	}
+	return builder.String()
+}
+
+// 背离类型描述
+func divergenceTypeFmt(divType int) string {
+	switch divType {
+	case DivergenceRegularBull:
+		return "常规底背离"
+	case DivergenceRegularBear:
+		return "常规顶背离"
+	case DivergenceHiddenBull:
+		return "隐藏底背离"
+	case DivergenceHiddenBear:
+		return "隐藏顶背离"
+	}
+	return ""
+}
+
+// 背离通知消息
+func divergenceMsgFmt(info *binanceFapi.SymbolInfo, cycle string, klines []binanceFapi.KLine, d Divergence) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("     ---- 【  %s %s %s%s 】 ---- \n", info.Symbol, cycle, d.Indicator, divergenceTypeFmt(d.Type)))
+	builder.WriteString(fmt.Sprintf("价格: %.4f(%.2f%%)\n", info.Price, info.Change))
+	timeFmt := "01-02 15:04"
+	builder.WriteString(fmt.Sprintf("波段1: %s 价格 %.4f %s %.4f\n", time.Unix(klines[d.From.Index].OpenTime/1000, 0).Format(timeFmt), d.From.Price, d.Indicator, d.FromValue))
+	builder.WriteString(fmt.Sprintf("波段2: %s 价格 %.4f %s %.4f\n", time.Unix(klines[d.To.Index].OpenTime/1000, 0).Format(timeFmt), d.To.Price, d.Indicator, d.ToValue))
+	builder.WriteString(fmt.Sprintf("时间: %s", time.Now().Format("2006-01-02 15:04:05")))
+	return builder.String()
+}
+
+// MACD 柱状图描述
+func macdHistMsgFmt(res MacdHistResult) string {
+	color := "红柱"
+	if res.Sign == 2 {
+		color = "绿柱"
+	}
+	msg := "柱体: " + color
+	switch res.Trend {
+	case 1:
+		msg += fmt.Sprintf(" 连续%d根放大", res.Streak)
+	case 2:
+		msg += fmt.Sprintf(" 连续%d根缩小", res.Streak)
+	}
+	if res.PrevArea > 0 {
+		msg += fmt.Sprintf(" 面积 %.4f/前段 %.4f", res.Area, res.PrevArea)
+	}
+	switch res.AreaDivergence {
+	case 1:
+		msg += " 面积底背离"
+	case 2:
+		msg += " 面积顶背离"
+	}
+	switch res.ZeroApproach {
+	case 1:
+		msg += " DIF自上方接近0轴"
+	case 2:
+		msg += " DIF自下方接近0轴"
+	}
+	return msg + "\n"
+}
+
+// 缠论结构描述
+func chanMsgFmt(res ChanResult) string {
+	pivot := res.Pivots[len(res.Pivots)-1]
+	msg := fmt.Sprintf("中枢: [%.4f, %.4f]", pivot.ZD, pivot.ZG)
+	switch res.PivotBreak {
+	case 1:
+		msg += " 价格向上离开中枢"
+	case 2:
+		msg += " 价格向下离开中枢"
+	}
+	if len(res.Strokes) > 0 {
+		if res.Strokes[len(res.Strokes)-1].Direction == 1 {
+			msg += " 末笔向上"
+		} else {
+			msg += " 末笔向下"
+		}
+	}
+	return msg + "\n"
+}
+
+// 缠论买卖点名称，如 一买 / 三卖
+func chanPointName(point ChanPoint) string {
+	classes := []string{"", "一", "二", "三"}
+	name := ""
+	if point.Class >= 1 && point.Class <= 3 {
+		name = classes[point.Class]
+	}
+	if point.Side == 1 {
+		return name + "买"
+	}
+	return name + "卖"
+}
+
+// 缠论买卖点通知消息
+func chanPointMsgFmt(info *binanceFapi.SymbolInfo, cycle string, point ChanPoint) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("     ---- 【  ‼️ %s %s 缠论%s 】 ---- \n", info.Symbol, cycle, chanPointName(point)))
+	builder.WriteString(fmt.Sprintf("价格: %.4f(%.2f%%)\n", info.Price, info.Change))
+	builder.WriteString(fmt.Sprintf("买卖点: %.4f\n", point.Price))
+	builder.WriteString(fmt.Sprintf("中枢: [%.4f, %.4f]\n", point.Pivot.ZD, point.Pivot.ZG))
+	if point.Class == 1 {
+		builder.WriteString(fmt.Sprintf("MACD面积: %.4f (进入段 %.4f)\n", point.Area, point.PrevArea))
+	}
+	builder.WriteString(fmt.Sprintf("时间: %s", time.Now().Format("2006-01-02 15:04:05")))
+	return builder.String()
+}
+
+// SMC 描述
+func smcMsgFmt(res SMCResult) string {
+	var builder strings.Builder
+	msg := "SMC:"
+	switch res.Trend {
+	case 1:
+		msg += " 结构看涨"
+	case 2:
+		msg += " 结构看跌"
+	}
+	if res.Signal != "" {
+		msg += " " + res.Signal
+	}
+	switch res.Zone {
+	case 1:
+		msg += " 溢价区"
+	case 2:
+		msg += " 折价区"
+	case 3:
+		msg += " 平衡区"
+	}
+	switch res.Sweep {
+	case 1:
+		msg += " 扫下方流动性"
+	case 2:
+		msg += " 扫上方流动性"
+	}
+	builder.WriteString(msg + "\n")
+	if res.Support > 0 || res.Resistance > 0 {
+		builder.WriteString(fmt.Sprintf("支撑/压力: %.4f / %.4f\n", res.Support, res.Resistance))
+	}
+	if res.Fvg.Type > 0 {
+		builder.WriteString("FVG: " + res.Fvg.String() + "\n")
+	}
+	if res.Ob.Type > 0 {
+		builder.WriteString("OB: " + res.Ob.String() + "\n")
+	}
+	return builder.String()
+}
+
+// 全部K线形态名称
+func candlePatternsFmt(patterns []CandlePattern) string {
+	names := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		names = append(names, p.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+// 关键位置K线形态描述，如 看涨吞没(波段低点)
+func candleSignalFmt(patterns []CandlePattern) string {
+	var parts []string
+	for _, p := range patterns {
+		if p.Meaningful {
+			parts = append(parts, fmt.Sprintf("%s(%s)", p.Name, p.Context))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// 几何形态描述
+func chartPatternMsgFmt(p ChartPattern) string {
+	switch p.Breakout {
+	case 1, 2:
+		dir := "向上突破"
+		level := p.Upper
+		if p.Breakout == 2 {
+			dir = "向下突破"
+			level = p.Lower
+		}
+		vol := "未放量"
+		if p.VolumeConfirmed {
+			vol = "放量"
+		}
+		return fmt.Sprintf("形态: %s %s %.4f (%s) 目标 %.4f\n", p.Name, dir, level, vol, p.Target)
+	}
+	return fmt.Sprintf("形态: %s 区间 [%.4f, %.4f]\n", p.Name, p.Lower, p.Upper)
+}
+
+// 成交量分布描述
+func volumeProfileMsgFmt(res VolumeProfileResult) string {
+	window := res.Profiles[0]
+	msg := fmt.Sprintf("筹码: POC %.4f 价值区 [%.4f, %.4f]", window.Poc, window.Val, window.Vah)
+	switch res.PocSignal {
+	case 1:
+		msg += " 收复POC"
+	case 2:
+		msg += " 跌破POC"
+	}
+	switch res.OpenOutside {
+	case 1:
+		msg += " 开盘高于上时段价值区"
+	case 2:
+		msg += " 开盘低于上时段价值区"
+	}
+	return msg + "\n"
+}
+
+// 枢轴点方法简称
+func pivotMethodFmt(method string) string {
+	switch method {
+	case PivotFibonacci:
+		return "斐波"
+	case PivotCamarilla:
+		return "卡玛"
+	case PivotWoodie:
+		return "伍迪"
+	}
+	return "经典"
+}
+
+// 枢轴引用描述，如 1d经典R1 123.4500
+func pivotRefFmt(ref PivotRef) string {
+	return fmt.Sprintf("%s%s%s %.4f", ref.Timeframe, pivotMethodFmt(ref.Method), ref.Level.Name, ref.Level.Price)
+}
+
+// 枢轴点描述：最近上下方枢轴及突破
+func pivotMsgFmt(res PivotResult) string {
+	var parts []string
+	if res.Above.Level.Name != "" {
+		parts = append(parts, "上方 "+pivotRefFmt(res.Above))
+	}
+	if res.Below.Level.Name != "" {
+		parts = append(parts, "下方 "+pivotRefFmt(res.Below))
+	}
+	msg := "枢轴: " + strings.Join(parts, " / ") + "\n"
+	if len(res.Breaks) > 0 {
+		breaks := make([]string, 0, len(res.Breaks))
+		for _, ref := range res.Breaks {
+			breaks = append(breaks, pivotRefFmt(ref))
+		}
+		msg += "枢轴突破: " + strings.Join(breaks, ", ") + "\n"
+	}
+	return msg
+}
+
+// 斐波那契描述：波段、回撤与扩展价位、黄金口袋
+func fibMsgFmt(res FibResult) string {
+	direction := "上涨"
+	if res.Direction == 2 {
+		direction = "下跌"
+	}
+	msg := fmt.Sprintf("斐波: %s波段 %.4f → %.4f", direction, res.Start, res.End)
+	if res.EnterPocket {
+		msg += " (进入黄金口袋)"
+	} else if res.InPocket {
+		msg += " (位于黄金口袋)"
+	}
+	msg += "\n回撤:"
+	for _, level := range res.Retracements {
+		msg += fmt.Sprintf(" %.3f=%.4f", level.Ratio, level.Price)
+	}
+	msg += "\n扩展:"
+	for _, level := range res.Extensions {
+		msg += fmt.Sprintf(" %.3f=%.4f", level.Ratio, level.Price)
+	}
+	return msg + "\n"
+}
+
+// 多周期共振描述，如 共振: 看涨 4/6 (67%) [4h:多头/RSI偏多/MACD多 ...]
+func confluenceMsgFmt(res ConfluenceResult) string {
+	return fmt.Sprintf("共振: %s %d/%d (%.0f%%) [%s]\n", directionFmt(res.Bias, "看涨", "看跌"),
+		res.Score, res.Max, res.Percent(), strings.Join(res.Details, " "))
+}
+
+// 相对强弱描述，如 相对BTC: 跑赢12根 超额+3.20% β1.35 ρ0.82 RSI61.2 MACD金叉
+func relativeStrengthMsgFmt(rs RelativeStrength) string {
+	name := strings.TrimSuffix(rs.Benchmark, "USDT")
+	msg := fmt.Sprintf("相对%s: ", name)
+	if rs.Streak > 0 {
+		msg += fmt.Sprintf("跑赢%d根 ", rs.Streak)
+	} else if rs.Streak < 0 {
+		msg += fmt.Sprintf("跑输%d根 ", -rs.Streak)
+	}
+	msg += fmt.Sprintf("超额%+.2f%% β%.2f ρ%.2f RSI%.1f", rs.RsChange, rs.Beta, rs.Correlation, rs.RsRsi)
+	switch rs.RsCross {
+	case 1:
+		msg += " MACD金叉"
+	case 2:
+		msg += " MACD死叉"
+	}
+	return msg + "\n"
+}
+
+// K线表示方式说明，原始K线时为空
+func transformFmt(transform string) string {
+	switch transform {
+	case TransformHeikinAshi:
+		return " (平均K线)"
+	case TransformRenko:
+		return " (砖形图)"
+	case TransformRange:
+		return " (等幅K线)"
+	}
+	return ""
+}
+
+// 周期时长，支持任意 Binance 周期及自定义周期，无效周期返回错误
+func CycleDurationFmt(cycle string) (time.Duration, error) {
+	spec, err := parseCycle(cycle)
+	if err != nil {
+		return 0, err
+	}
+	return spec.Duration, nil
 }
 
 // 获取周期触发次数
@@ -143,3 +642,46 @@ func GetCycleDelay(cycle string) int {
 	}
 	return 0
 }
+
+// 获取周期一目均衡表参数，周期未配置时使用 Benchmark，缺省为传统 9/26/52/26
+func GetIchimokuParams(cycle string) config.Ichimoku {
+	params := config.Cfg.Benchmark.Ichimoku
+	for _, c := range config.Cfg.Cycles {
+		if c.Cycle == cycle && c.Ichimoku != nil {
+			params = *c.Ichimoku
+		}
+	}
+	if params.Tenkan <= 0 {
+		params.Tenkan = 9
+	}
+	if params.Kijun <= 0 {
+		params.Kijun = 26
+	}
+	if params.SenkouB <= 0 {
+		params.SenkouB = 52
+	}
+	if params.Displacement <= 0 {
+		params.Displacement = params.Kijun
+	}
+	return params
+}
+
+// 获取周期震荡指标开关及参数，周期未配置时使用 Benchmark
+func GetOscillatorParams(cycle string) config.Oscillator {
+	for _, c := range config.Cfg.Cycles {
+		if c.Cycle == cycle && c.Oscillator != nil {
+			return *c.Oscillator
+		}
+	}
+	return config.Cfg.Benchmark.Oscillator
+}
+
+// 获取周期K线表示方式，周期未配置时使用 Benchmark
+func GetTransformParams(cycle string) config.Transform {
+	for _, c := range config.Cfg.Cycles {
+		if c.Cycle == cycle && c.Transform != nil {
+			return *c.Transform
+		}
+	}
+	return config.Cfg.Benchmark.Transform
+}
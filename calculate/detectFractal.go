@@ -4,8 +4,10 @@ import "IndicatorTask/binanceFapi"
 
 // K线对象简化版用于包含处理
 type chanKLine struct {
-	High float64
-	Low  float64
+	High    float64
+	Low     float64
+	HighIdx int // 最高价所在原始K线下标
+	LowIdx  int // 最低价所在原始K线下标
 }
 
 // 检测分型 (缠论标准：包含处理 + 分型识别)
@@ -62,17 +64,25 @@ func processInclusion(klines []binanceFapi.KLine) []chanKLine {
 
 		if isIncluded {
 			// 如果有包含关系，合并
+			merged := last
 			if isUp {
 				// 向上：高高，低高
-				newHigh := max(last.High, currH)
-				newLow := max(last.Low, currL)
-				res[len(res)-1] = chanKLine{High: newHigh, Low: newLow}
+				if currH > last.High {
+					merged.High, merged.HighIdx = currH, i
+				}
+				if currL > last.Low {
+					merged.Low, merged.LowIdx = currL, i
+				}
 			} else {
 				// 向下：低低，高低
-				newHigh := min(last.High, currH)
-				newLow := min(last.Low, currL)
-				res[len(res)-1] = chanKLine{High: newHigh, Low: newLow}
+				if currH < last.High {
+					merged.High, merged.HighIdx = currH, i
+				}
+				if currL < last.Low {
+					merged.Low, merged.LowIdx = currL, i
+				}
 			}
+			res[len(res)-1] = merged
 		} else {
 			// 没有包含关系，判断新方向并加入
 			if currH > last.High {
@@ -80,12 +90,27 @@ func processInclusion(klines []binanceFapi.KLine) []chanKLine {
 			} else if currL < last.Low {
 				isUp = false
 			}
-			res = append(res, chanKLine{High: currH, Low: currL})
+			res = append(res, chanKLine{High: currH, Low: currL, HighIdx: i, LowIdx: i})
 		}
 	}
 	return res
 }
 
+// 最近一个已确认分型对应的原始K线下标 (shape: 1 顶分型, 2 底分型)，未找到返回 -1
+func lastFractalIndex(klines []binanceFapi.KLine, shape int) int {
+	processed := processInclusion(klines)
+	for i := len(processed) - 2; i >= 1; i-- {
+		k1, k2, k3 := processed[i-1], processed[i], processed[i+1]
+		if shape == 1 && k2.High > k1.High && k2.High > k3.High {
+			return k2.HighIdx
+		}
+		if shape == 2 && k2.Low < k1.Low && k2.Low < k3.Low {
+			return k2.LowIdx
+		}
+	}
+	return -1
+}
+
 func max(a, b float64) float64 {
 	if a > b {
 		return a
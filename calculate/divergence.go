@@ -0,0 +1,102 @@
+package calculate
+
+import (
+	"IndicatorTask/binanceFapi"
+	"IndicatorTask/config"
+)
+
+// 背离类型
+const (
+	DivergenceRegularBull = 1 // 常规底背离：价格更低的低点，指标更高的低点
+	DivergenceRegularBear = 2 // 常规顶背离：价格更高的高点，指标更低的高点
+	DivergenceHiddenBull  = 3 // 隐藏底背离：价格更高的低点，指标更低的低点
+	DivergenceHiddenBear  = 4 // 隐藏顶背离：价格更低的高点，指标更高的高点
+)
+
+// Divergence 单次背离，记录两个波段点及对应指标值
+type Divergence struct {
+	Indicator string // RSI / MACD / HIST
+	Type      int
+	From      swingPoint
+	To        swingPoint
+	FromValue float64
+	ToValue   float64
+}
+
+// 比较最近两个波段点与指标值
+func compareSwings(points []swingPoint, values []float64, isHigh bool) (int, bool) {
+	if len(points) < 2 {
+		return 0, false
+	}
+	a, b := points[len(points)-2], points[len(points)-1]
+	va, vb := values[a.Index], values[b.Index]
+
+	if isHigh {
+		if b.Price > a.Price && vb < va {
+			return DivergenceRegularBear, true
+		}
+		if b.Price < a.Price && vb > va {
+			return DivergenceHiddenBear, true
+		}
+		return 0, false
+	}
+	if b.Price < a.Price && vb > va {
+		return DivergenceRegularBull, true
+	}
+	if b.Price > a.Price && vb < va {
+		return DivergenceHiddenBull, true
+	}
+	return 0, false
+}
+
+// 检测价格与 RSI、MACD 线、MACD 柱之间的背离
+// 仅返回最新波段点在最近 2*window 根K线内确认的背离，避免每轮重复报告旧背离
+func detectDivergences(klines []binanceFapi.KLine, series map[string][]float64, params config.Divergence) []Divergence {
+	if params.Window <= 0 {
+		params.Window = 5
+	}
+	if params.Lookback <= 0 {
+		params.Lookback = 60
+	}
+	n := len(klines)
+	if n < params.Window*2+1 {
+		return nil
+	}
+
+	start := n - params.Lookback
+	if start < 0 {
+		start = 0
+	}
+	highs, lows := findSwings(klines, params.Window, start)
+	recent := n - 1 - params.Window*2
+
+	var res []Divergence
+	for _, name := range []string{"RSI", "MACD", "HIST"} {
+		values, ok := series[name]
+		if !ok || len(values) != n {
+			continue
+		}
+		for _, side := range []struct {
+			points []swingPoint
+			isHigh bool
+		}{{highs, true}, {lows, false}} {
+			divType, found := compareSwings(side.points, values, side.isHigh)
+			if !found {
+				continue
+			}
+			a, b := side.points[len(side.points)-2], side.points[len(side.points)-1]
+			if b.Index < recent {
+				continue
+			}
+			res = append(res, Divergence{
+				Indicator: name,
+				Type:      divType,
+				From:      a,
+				To:        b,
+				FromValue: values[a.Index],
+				ToValue:   values[b.Index],
+			})
+		}
+	}
+	return res
+}
@@ -0,0 +1,140 @@
+package calculate
+
+import (
+	"IndicatorTask/binanceFapi"
+	"IndicatorTask/config"
+)
+
+// 斐波那契回撤与扩展比例
+var (
+	fibRetracements = []float64{0.382, 0.5, 0.618, 0.786}
+	fibExtensions   = []float64{1.272, 1.618, 2.0}
+)
+
+// 黄金口袋 (0.618 - 0.65 回撤)
+const (
+	fibPocketLow  = 0.618
+	fibPocketHigh = 0.65
+)
+
+// FibLevel 斐波那契价位
+type FibLevel struct {
+	Ratio float64
+	Price float64
+}
+
+// FibResult 最近一段显著波段的斐波那契回撤与扩展
+type FibResult struct {
+	Direction    int // 0: 无, 1: 上涨波段 (低点->高点), 2: 下跌波段 (高点->低点)
+	StartIndex   int
+	EndIndex     int
+	Start        float64 // 波段起点价格
+	End          float64 // 波段终点价格
+	Retracements []FibLevel
+	Extensions   []FibLevel
+	PocketLow    float64 // 黄金口袋下沿
+	PocketHigh   float64 // 黄金口袋上沿
+	InPocket     bool    // 最新收盘位于黄金口袋
+	EnterPocket  bool    // 最新K线收盘进入黄金口袋
+}
+
+// 按比例计算价位：回撤自终点向起点，扩展自起点向终点方向
+func (f FibResult) price(ratio float64, extension bool) float64 {
+	if extension {
+		return f.Start + (f.End-f.Start)*ratio
+	}
+	return f.End - (f.End-f.Start)*ratio
+}
+
+// 波段转折点：缠论笔端点 (未开启缠论时退回波段高低点) 或 SMC 波段高低点
+func fibPivots(klines []binanceFapi.KLine, strokes []chanStroke, params config.Fibonacci) []chanFractal {
+	if params.Source == "chan" && len(strokes) > 0 {
+		points := []chanFractal{strokes[0].Start}
+		for _, s := range strokes {
+			points = append(points, s.End)
+		}
+		return points
+	}
+	start := len(klines) - params.Lookback
+	if start < 0 {
+		start = 0
+	}
+	highs, lows := findSwings(klines, params.Window, start)
+	return alternateSwings(highs, lows)
+}
+
+// 选取最近一段幅度不低于 MinRange% 且未被跌破/突破起点的波段，计算回撤与扩展
+func calculateFibonacci(klines []binanceFapi.KLine, strokes []chanStroke, params config.Fibonacci) FibResult {
+	if params.Window <= 0 {
+		params.Window = 5
+	}
+	if params.Lookback <= 0 {
+		params.Lookback = 200
+	}
+	if params.MinRange <= 0 {
+		params.MinRange = 3
+	}
+	res := FibResult{}
+	n := len(klines)
+	if n < 3 {
+		return res
+	}
+	points := fibPivots(klines, strokes, params)
+
+	for i := len(points) - 1; i >= 1; i-- {
+		a, b := points[i-1], points[i]
+		if a.Type == b.Type || a.Price <= 0 {
+			continue
+		}
+		// 终点之后若出现更极端价格，波段延伸至该处
+		end, endIndex := b.Price, b.Index
+		for j := b.Index + 1; j < n; j++ {
+			if b.Type == 1 && klines[j].High > end {
+				end, endIndex = klines[j].High, j
+			} else if b.Type == 2 && klines[j].Low < end {
+				end, endIndex = klines[j].Low, j
+			}
+		}
+		if abs(end-a.Price)/a.Price*100 < params.MinRange {
+			continue
+		}
+		// 收盘越过起点，波段失效
+		valid := true
+		for j := endIndex; j < n; j++ {
+			if (b.Type == 1 && klines[j].Close < a.Price) || (b.Type == 2 && klines[j].Close > a.Price) {
+				valid = false
+				break
+			}
+		}
+		if !valid {
+			break
+		}
+
+		res.Direction = 2
+		if b.Type == 1 {
+			res.Direction = 1
+		}
+		res.StartIndex, res.EndIndex = a.Index, endIndex
+		res.Start, res.End = a.Price, end
+		break
+	}
+	if res.Direction == 0 {
+		return res
+	}
+
+	for _, r := range fibRetracements {
+		res.Retracements = append(res.Retracements, FibLevel{Ratio: r, Price: res.price(r, false)})
+	}
+	for _, r := range fibExtensions {
+		res.Extensions = append(res.Extensions, FibLevel{Ratio: r, Price: res.price(r, true)})
+	}
+	res.PocketLow = min(res.price(fibPocketLow, false), res.price(fibPocketHigh, false))
+	res.PocketHigh = max(res.price(fibPocketLow, false), res.price(fibPocketHigh, false))
+
+	inPocket := func(price float64) bool {
+		return price >= res.PocketLow && price <= res.PocketHigh
+	}
+	res.InPocket = inPocket(klines[n-1].Close)
+	res.EnterPocket = res.InPocket && res.EndIndex < n-1 && !inPocket(klines[n-2].Close)
+	return res
+}
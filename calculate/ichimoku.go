@@ -0,0 +1,94 @@
+package calculate
+
+import (
+	"IndicatorTask/binanceFapi"
+	"IndicatorTask/config"
+)
+
+// IchimokuResult 一目均衡表最新值及衍生状态
+type IchimokuResult struct {
+	Tenkan        float64
+	Kijun         float64
+	SenkouA       float64 // 当前K线下方/上方的云 (已位移)
+	SenkouB       float64
+	Chikou        float64
+	CloudPosition int // 0: 无, 1: 云上, 2: 云中, 3: 云下
+	TkCross       int // 0: 无, 1: 金叉, 2: 死叉
+	CloudTwist    int // 0: 无, 1: 转多, 2: 转空 (领先云)
+	ChikouConfirm int // 0: 无, 1: 多头确认, 2: 空头确认
+}
+
+// 区间最高最低的中值
+func midpoint(klines []binanceFapi.KLine, end, period int) float64 {
+	high := klines[end].High
+	low := klines[end].Low
+	for i := end - period + 1; i < end; i++ {
+		high = max(high, klines[i].High)
+		low = min(low, klines[i].Low)
+	}
+	return (high + low) / 2
+}
+
+// 计算一目均衡表
+func calculateIchimoku(klines []binanceFapi.KLine, params config.Ichimoku) IchimokuResult {
+	n := len(klines)
+	res := IchimokuResult{}
+	// 当前云需要 位移+先行带B 根K线，另外多留一根用于判断交叉
+	if n < params.SenkouB+params.Displacement+1 || n < params.Kijun+1 {
+		return res
+	}
+
+	last := n - 1
+	prev := n - 2
+
+	res.Tenkan = midpoint(klines, last, params.Tenkan)
+	res.Kijun = midpoint(klines, last, params.Kijun)
+	res.Chikou = klines[last].Close
+
+	// 当前云：由 Displacement 根之前的数据计算
+	base := last - params.Displacement
+	res.SenkouA = (midpoint(klines, base, params.Tenkan) + midpoint(klines, base, params.Kijun)) / 2
+	res.SenkouB = midpoint(klines, base, params.SenkouB)
+
+	// 价格与云的关系
+	cloudTop := max(res.SenkouA, res.SenkouB)
+	cloudBottom := min(res.SenkouA, res.SenkouB)
+	closePrice := klines[last].Close
+	switch {
+	case closePrice > cloudTop:
+		res.CloudPosition = 1
+	case closePrice < cloudBottom:
+		res.CloudPosition = 3
+	default:
+		res.CloudPosition = 2
+	}
+
+	// TK 交叉
+	prevTenkan := midpoint(klines, prev, params.Tenkan)
+	prevKijun := midpoint(klines, prev, params.Kijun)
+	if prevTenkan <= prevKijun && res.Tenkan > res.Kijun {
+		res.TkCross = 1
+	} else if prevTenkan >= prevKijun && res.Tenkan < res.Kijun {
+		res.TkCross = 2
+	}
+
+	// 云扭转：领先云 (当前K线计算、向前位移) 的 A/B 交换位置
+	leadA := (res.Tenkan + res.Kijun) / 2
+	leadB := midpoint(klines, last, params.SenkouB)
+	prevLeadA := (prevTenkan + prevKijun) / 2
+	prevLeadB := midpoint(klines, prev, params.SenkouB)
+	if prevLeadA <= prevLeadB && leadA > leadB {
+		res.CloudTwist = 1
+	} else if prevLeadA >= prevLeadB && leadA < leadB {
+		res.CloudTwist = 2
+	}
+
+	// 迟行线确认：当前收盘与 Displacement 根之前的K线比较
+	if closePrice > klines[base].High {
+		res.ChikouConfirm = 1
+	} else if closePrice < klines[base].Low {
+		res.ChikouConfirm = 2
+	}
+
+	return res
+}
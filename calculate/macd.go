@@ -74,3 +74,133 @@ func detectCrosses(klines []binanceFapi.KLine, macd, signalLine []float64) (int,
 	}
 	return 0, 0
 }
+
+// 柱状图同号连续区段
+type histSegment struct {
+	Start int
+	End   int
+	Sign  int     // 1: 红柱 (>0), 2: 绿柱 (<0)
+	Area  float64 // 柱体面积绝对值之和
+}
+
+// MacdHistResult 柱状图动能与面积分析结果
+type MacdHistResult struct {
+	Hist           float64
+	Sign           int // 1: 红柱, 2: 绿柱
+	Trend          int // 0: 无, 1: 放大, 2: 缩小
+	Streak         int // 连续放大/缩小根数
+	Area           float64
+	PrevArea       float64 // 前一同色区段面积
+	AreaDivergence int     // 0: 无, 1: 底背离 (价格新低绿柱面积缩小), 2: 顶背离 (价格新高红柱面积缩小)
+	ZeroApproach   int     // 0: 无, 1: MACD 线自上方接近0轴, 2: 自下方接近0轴
+}
+
+// 按正负号切分柱状图区段，0 值并入前一区段
+func histSegments(histogram []float64) []histSegment {
+	var segments []histSegment
+	for i, h := range histogram {
+		sign := 0
+		if h > 0 {
+			sign = 1
+		} else if h < 0 {
+			sign = 2
+		}
+		if len(segments) > 0 && (sign == 0 || segments[len(segments)-1].Sign == sign) {
+			last := &segments[len(segments)-1]
+			last.End = i
+			last.Area += abs(h)
+			continue
+		}
+		if sign == 0 {
+			continue
+		}
+		segments = append(segments, histSegment{Start: i, End: i, Sign: sign, Area: abs(h)})
+	}
+	return segments
+}
+
+// 区段内价格极值：红柱取最高价，绿柱取最低价
+func segmentExtreme(klines []binanceFapi.KLine, seg histSegment) float64 {
+	if seg.Sign == 1 {
+		high := klines[seg.Start].High
+		for i := seg.Start + 1; i <= seg.End; i++ {
+			high = max(high, klines[i].High)
+		}
+		return high
+	}
+	low := klines[seg.Start].Low
+	for i := seg.Start + 1; i <= seg.End; i++ {
+		low = min(low, klines[i].Low)
+	}
+	return low
+}
+
+// 柱状图分析
+func analyzeMacdHistogram(klines []binanceFapi.KLine, macd, histogram []float64, zeroRatio float64) MacdHistResult {
+	n := len(histogram)
+	res := MacdHistResult{}
+	if n < 3 || len(klines) != n {
+		return res
+	}
+	if zeroRatio <= 0 {
+		zeroRatio = 0.1
+	}
+
+	res.Hist = histogram[n-1]
+
+	// 连续放大/缩小
+	for i := n - 1; i > 0; i-- {
+		curr, prev := abs(histogram[i]), abs(histogram[i-1])
+		trend := 0
+		if curr > prev {
+			trend = 1
+		} else if curr < prev {
+			trend = 2
+		}
+		if trend == 0 || (res.Trend != 0 && trend != res.Trend) || (histogram[i] > 0) != (histogram[i-1] > 0) {
+			break
+		}
+		res.Trend = trend
+		res.Streak++
+	}
+
+	// 面积与面积背离
+	segments := histSegments(histogram)
+	if len(segments) > 0 {
+		curr := segments[len(segments)-1]
+		res.Sign = curr.Sign
+		res.Area = curr.Area
+		if len(segments) >= 3 {
+			prev := segments[len(segments)-3]
+			res.PrevArea = prev.Area
+			if curr.Area < prev.Area {
+				currExt, prevExt := segmentExtreme(klines, curr), segmentExtreme(klines, prev)
+				if curr.Sign == 1 && currExt > prevExt {
+					res.AreaDivergence = 2
+				} else if curr.Sign == 2 && currExt < prevExt {
+					res.AreaDivergence = 1
+				}
+			}
+		}
+	}
+
+	// 接近0轴：本根首次进入阈值范围且仍在向0轴靠拢
+	lookback := 50
+	if n < lookback {
+		lookback = n
+	}
+	var maxAbs float64
+	for i := n - lookback; i < n; i++ {
+		maxAbs = max(maxAbs, abs(macd[i]))
+	}
+	threshold := maxAbs * zeroRatio
+	curr, prev := macd[n-1], macd[n-2]
+	if maxAbs > 0 && abs(curr) < threshold && abs(prev) >= threshold && abs(curr) < abs(prev) {
+		if curr > 0 {
+			res.ZeroApproach = 1
+		} else {
+			res.ZeroApproach = 2
+		}
+	}
+	return res
+}
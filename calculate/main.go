@@ -3,16 +3,40 @@ package calculate
 import (
 	"IndicatorTask/binanceFapi"
 	"IndicatorTask/config"
+	"IndicatorTask/store"
 	"IndicatorTask/utils/logger"
 	"IndicatorTask/utils/notify"
 
 	"context"
+	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/cryptoSelect/public/database"
 	"github.com/cryptoSelect/public/models"
 )
 
+// 单个 symbol 本轮计算得到的扩展指标结果
+type indicatorResult struct {
+	Ichimoku   IchimokuResult
+	Vwap       VwapResult
+	Supertrend TrendStopResult
+	Sar        TrendStopResult
+	VolumeFlow VolumeFlowResult
+	Oscillator OscillatorResult
+	MacdHist   MacdHistResult
+	Chan       ChanResult
+	Smc        SMCResult
+	Candles    []CandlePattern
+	Charts     []ChartPattern
+	Profile    VolumeProfileResult
+	Pivot      PivotResult
+	Fib        FibResult
+	Confluence ConfluenceResult
+	Relative   []RelativeStrength
+	Transform  string // MACD/RSI/分型所用K线表示方式，原始K线时为空
+}
+
 // 进行macd
 func Start(ctx context.Context, cycle string) {
 	// 增加延时，防止多周期协程同时操作 SymbolList 导致数据竞争
@@ -24,17 +48,12 @@ func Start(ctx context.Context, cycle string) {
 		binanceFapi.GetSymbols()
 	}
 
+	run := newCycleRun(cycle)
 	for _, symbolInfo := range binanceFapi.SymbolList {
-		// 重置信号状态，确保每个周期和每一轮都是独立计算
-		symbolInfo.CrossType = 0
-		symbolInfo.Shape = 0
-		symbolInfo.VpSignal = ""
-
 		symbol := symbolInfo.Symbol
-		Msg := ""
 
 		// 获取K线数据
-		klines, err := binanceFapi.GetContractKlines(symbol, cycle)
+		klines, err := getCycleKlines(symbol, cycle)
 		if err != nil {
 			logger.Log.Error("错误:", map[string]interface{}{"err": err})
 			continue
@@ -50,81 +69,402 @@ func Start(ctx context.Context, cycle string) {
 			continue
 		}
 
-		// 计算涨跌幅
-		latestKline := klines[len(klines)-1]
-		symbolInfo.Change = (latestKline.Close - latestKline.Open) / latestKline.Open * 100
+		run.keepKlines(symbol, klines)
+		run.analyze(symbolInfo, klines, false)
+	}
+
+	// 自定义篮子指数，与普通交易对走相同的计算流程
+	run.analyzeBaskets()
+
+	// 配对价差监控
+	run.analyzePairs()
 
-		// 处理收线价格
-		closes := binanceFapi.ClosePrice(klines)
+	run.finish()
+}
+
+// 单轮计算中跨交易对共享的数据
+type cycleRun struct {
+	cycle string
+
+	// 相对强弱基准K线，每轮只获取一次
+	rsParams   config.RelativeStrength
+	benchmarks map[string][]binanceFapi.KLine
 
-		// 计算MACD (快线12，慢线26，信号线9)
-		macd, signalLine, _ := calculateMACD(closes)
+	// 各交易对截面数据，用于市场排行
+	screener     config.Screener
+	screenerRows []screenerRow
 
-		// 计算交叉
-		crossType, klineIndex := detectCrosses(klines, macd, signalLine)
+	// 各交易对宽度数据
+	breadth     config.Breadth
+	breadthRows []breadthRow
 
-		// 计算RSI
-		rsiValue := GetRsi(closes)
+	// 收盘价序列及待推送提醒，聚类完成后统一推送
+	cluster       config.Cluster
+	clusterRows   []clusterSeries
+	clusterAlerts []clusterAlert
+
+	// 板块统计数据
+	sector     config.Sector
+	sectorRows []sectorRow
+
+	// 篮子成分及配对交易对K线
+	klineCache map[string][]binanceFapi.KLine
+}
 
-		// MACD 交叉判断
-		if klineIndex != 0 {
-			symbolInfo.CrossType = crossType
+func newCycleRun(cycle string) *cycleRun {
+	r := &cycleRun{
+		cycle:      cycle,
+		rsParams:   config.Cfg.Benchmark.RelativeStrength,
+		screener:   config.Cfg.Benchmark.Screener,
+		breadth:    config.Cfg.Benchmark.Breadth,
+		cluster:    config.Cfg.Benchmark.Cluster,
+		sector:     config.Cfg.Benchmark.Sector,
+		klineCache: make(map[string][]binanceFapi.KLine),
+	}
+	if r.rsParams.Enable {
+		r.benchmarks = loadRsBenchmarks(cycle, r.rsParams)
+	}
+	if r.cluster.Window <= 0 {
+		r.cluster.Window = 100
+	}
+	return r
+}
+
+// 收集市场级统计所需的单个交易对数据
+func (r *cycleRun) collect(symbolInfo *binanceFapi.SymbolInfo, klines []binanceFapi.KLine, ind *indicatorResult) {
+	symbol := symbolInfo.Symbol
+	if r.screener.Enable {
+		r.screenerRows = append(r.screenerRows, newScreenerRow(symbolInfo, klines, ind))
+	}
+	if r.breadth.Enable {
+		r.breadthRows = append(r.breadthRows, newBreadthRow(klines, r.breadth))
+	}
+	if r.sector.Enable {
+		if row, ok := newSectorRow(symbol, klines, r.sector); ok {
+			r.sectorRows = append(r.sectorRows, row)
+		}
+	}
+	if r.cluster.Enable {
+		if series, ok := newClusterSeries(symbol, klines, r.cluster.Window); ok {
+			r.clusterRows = append(r.clusterRows, series)
 		}
+	}
+}
 
-		// 计算缠论分型
-		shape := detectFractal(klines)
+// 本轮所有交易对计算完成后的市场级统计
+func (r *cycleRun) finish() {
+	// 市场排行
+	if r.screener.Enable {
+		runScreener(r.cycle, r.screenerRows, r.screener)
+	}
+
+	// 市场宽度
+	if r.breadth.Enable {
+		runBreadth(r.cycle, r.breadthRows, r.breadth)
+	}
+
+	// 板块汇总与轮动
+	if r.sector.Enable {
+		runSectors(r.cycle, r.sectorRows)
+	}
+
+	// 相关性聚类
+	if r.cluster.Enable {
+		runClustering(r.cycle, r.clusterRows, r.clusterAlerts, r.cluster)
+	}
+}
+
+// 单个交易对的指标计算、入库与提醒；synthetic 为篮子指数等合成交易对，不参与市场级统计
+func (r *cycleRun) analyze(symbolInfo *binanceFapi.SymbolInfo, klines []binanceFapi.KLine, synthetic bool) {
+	cycle := r.cycle
+	symbol := symbolInfo.Symbol
+	Msg := ""
+
+	// 重置信号状态，确保每个周期和每一轮都是独立计算
+	symbolInfo.CrossType = 0
+	symbolInfo.Shape = 0
+	symbolInfo.VpSignal = ""
+	symbolInfo.SMCSignal = ""
+	symbolInfo.Fvg = ""
+	symbolInfo.Ob = ""
+
+	// 计算涨跌幅
+	latestKline := klines[len(klines)-1]
+	symbolInfo.Change = (latestKline.Close - latestKline.Open) / latestKline.Open * 100
+
+	// MACD、RSI、分型及背离所用K线 (可按周期配置为平均K线 / 砖形图 / 等幅K线)，其余指标使用原始K线
+	src, transformed := transformKlines(klines, GetTransformParams(cycle))
 
-		// symbolInfo 基础信息
-		symbolInfo.Rsi = rsiValue
+	// 处理收线价格
+	closes := binanceFapi.ClosePrice(src)
+
+	// 计算MACD (快线12，慢线26，信号线9)
+	macd, signalLine, histogram := calculateMACD(closes)
+
+	// 计算交叉
+	crossType, klineIndex := detectCrosses(src, macd, signalLine)
+
+	// 计算RSI
+	rsiValue := GetRsi(closes)
+
+	// MACD 交叉判断
+	if klineIndex != 0 {
+		symbolInfo.CrossType = crossType
+	}
+
+	// 计算缠论分型
+	shape := detectFractal(src)
+
+	// symbolInfo 基础信息
+	symbolInfo.Rsi = rsiValue
+	if !synthetic {
 		symbolInfo.Rate = binanceFapi.GetRate(symbolInfo.Symbol)
-		symbolInfo.Price = latestKline.Close
-		takerBuyRatio := (latestKline.TakerBuyVolume / latestKline.Volume) * 100
-		symbolInfo.Volume = latestKline.Volume
-		symbolInfo.TakerBuyVolume = latestKline.TakerBuyVolume
-		symbolInfo.TakerBuyRatio = takerBuyRatio
+	}
+	symbolInfo.Price = latestKline.Close
+	takerBuyRatio := (latestKline.TakerBuyVolume / latestKline.Volume) * 100
+	symbolInfo.Volume = latestKline.Volume
+	symbolInfo.TakerBuyVolume = latestKline.TakerBuyVolume
+	symbolInfo.TakerBuyRatio = takerBuyRatio
+
+	// 量价分析
+	symbolInfo.VpSignal = detectVolumePrice(klines, takerBuyRatio)
+
+	// 记录本周期状态，供低级别周期做共振判断
+	confluence := config.Cfg.Benchmark.Confluence
+	var state cycleState
+	if confluence.Enable {
+		state = buildCycleState(closes, macd, signalLine, rsiValue, confluence)
+		updateCycleState(symbol, cycle, state)
+	}
 
-		// 量价分析
-		symbolInfo.VpSignal = detectVolumePrice(klines, takerBuyRatio)
+	// 扩展指标
+	ind := &indicatorResult{}
+	if transformed {
+		ind.Transform = GetTransformParams(cycle).Type
+	}
+	if smc := config.Cfg.Benchmark.Smc; smc.Enable {
+		ind.Smc = detectSMC(klines, smc)
+		symbolInfo.Support = ind.Smc.Support
+		symbolInfo.Resistance = ind.Smc.Resistance
+		symbolInfo.SMCSignal = ind.Smc.Signal
+		symbolInfo.Fvg = ind.Smc.Fvg.String()
+		symbolInfo.Ob = ind.Smc.Ob.String()
+	}
+	if ichimokuParams := GetIchimokuParams(cycle); ichimokuParams.Enable {
+		ind.Ichimoku = calculateIchimoku(klines, ichimokuParams)
+	}
+	if config.Cfg.Benchmark.Vwap.Enable {
+		ind.Vwap = calculateVwap(klines, symbolInfo.NextFundingTime, symbolInfo.RateCycle, config.Cfg.Benchmark.Vwap.BandTouch)
+	}
+	if st := config.Cfg.Benchmark.Supertrend; st.Enable {
+		ind.Supertrend = calculateSupertrend(klines, st.AtrPeriod, st.Multiplier)
+	}
+	if sar := config.Cfg.Benchmark.Sar; sar.Enable {
+		ind.Sar = calculateSAR(klines, sar.Step, sar.MaxStep)
+	}
+	if vf := config.Cfg.Benchmark.VolumeFlow; vf.Enable {
+		ind.VolumeFlow = calculateVolumeFlow(klines, vf)
+	}
+	ind.Oscillator = calculateOscillators(klines, GetOscillatorParams(cycle))
+	if config.Cfg.Benchmark.Macd.Histogram {
+		ind.MacdHist = analyzeMacdHistogram(src, macd, histogram, config.Cfg.Benchmark.Macd.ZeroRatio)
+	}
 
-		// 将分析结果入库
-		saveSymbolRecord(symbolInfo, cycle, klines, klineIndex)
+	if config.Cfg.Benchmark.Chan.Enable {
+		ind.Chan = buildChanStructure(klines, config.Cfg.Benchmark.Chan)
+		saveChanRecord(symbol, cycle, klines, ind.Chan)
 
-		// 判定是否属于“异常”情况（满足任意一个则发通知）
-		shouldNotify := false
+		// 缠论买卖点，新出现时作为高优先级提醒单独推送 (笔面积基于原始K线的 MACD 柱)
+		chanHist := histogram
+		if transformed {
+			_, _, chanHist = calculateMACD(binanceFapi.ClosePrice(klines))
+		}
+		for _, point := range detectChanPoints(ind.Chan, chanHist) {
+			if saveChanPoint(symbol, cycle, klines, ind.Chan, point) {
+				notify.PushPriority(symbol, cycle, notify.KindChanPoint, chanPointMsgFmt(symbolInfo, cycle, point))
+			}
+		}
+	}
 
-		// 1. MACD 交叉
-		if symbolInfo.CrossType != 0 {
-			shouldNotify = true
+	// K线形态，以 SMC 支撑压力与缠论中枢上下沿作为参考位
+	if candle := config.Cfg.Benchmark.Candle; candle.Enable {
+		levels := []float64{ind.Smc.Support, ind.Smc.Resistance}
+		if len(ind.Chan.Pivots) > 0 {
+			pivot := ind.Chan.Pivots[len(ind.Chan.Pivots)-1]
+			levels = append(levels, pivot.ZG, pivot.ZD)
 		}
+		ind.Candles = detectCandlePatterns(klines, levels, candle)
+	}
 
-		// 2. 缠论分型
-		if shape != 0 {
-			symbolInfo.Shape = shape
-			logger.Log.Info("缠论分型", map[string]interface{}{"symbol": symbolInfo.Symbol, "cycle": cycle, "shape": shape, "rsi": rsiValue})
-			shouldNotify = true
+	if chart := config.Cfg.Benchmark.ChartPattern; chart.Enable {
+		ind.Charts = detectChartPatterns(klines, chart)
+	}
+
+	if vp := config.Cfg.Benchmark.VolumeProfile; vp.Enable {
+		ind.Profile = calculateVolumeProfile(klines, vp)
+		saveVolumeProfiles(symbol, cycle, ind.Profile)
+	}
+
+	if fib := config.Cfg.Benchmark.Fibonacci; fib.Enable {
+		ind.Fib = calculateFibonacci(klines, ind.Chan.Strokes, fib)
+	}
+
+	if r.rsParams.Enable {
+		ind.Relative = calculateRelativeStrength(symbol, klines, r.benchmarks, r.rsParams)
+		saveRelativeStrength(symbol, cycle, ind.Relative)
+	}
+
+	// 枢轴点依赖交易所高级别K线，篮子指数不计算
+	if pp := config.Cfg.Benchmark.PivotPoint; pp.Enable && !synthetic {
+		ind.Pivot = calculatePivotPoints(symbol, klines, pp)
+		savePivotPoints(symbol, ind.Pivot)
+	}
+
+	// 背离检测，每个新确认的背离单独入库并推送
+	if div := config.Cfg.Benchmark.Divergence; div.Enable {
+		series := map[string][]float64{
+			"RSI":  calculateRsiSeries(closes, config.Cfg.Benchmark.Rsi.Period),
+			"MACD": macd,
+			"HIST": histogram,
 		}
+		for _, d := range detectDivergences(src, series, div) {
+			if saveDivergence(symbol, cycle, src, d) {
+				notify.PushKind(symbol, cycle, notify.KindDivergence, divergenceMsgFmt(symbolInfo, cycle, src, d))
+			}
+		}
+	}
+
+	// 多周期共振评分
+	if confluence.Enable {
+		ind.Confluence = calculateConfluence(symbol, cycle, signalBias(symbolInfo.CrossType, ind, state))
+	}
+
+	// 将分析结果入库
+	saveSymbolRecord(symbolInfo, cycle, src, klineIndex)
+	saveIndicatorRecord(symbolInfo.Symbol, cycle, ind)
+	if !synthetic {
+		r.collect(symbolInfo, klines, ind)
+	}
+
+	// 判定是否属于“异常”情况（满足任意一个则发通知）
+	shouldNotify := false
+
+	// 1. MACD 交叉
+	if symbolInfo.CrossType != 0 {
+		shouldNotify = true
+	}
+
+	// 2. 缠论分型
+	if shape != 0 {
+		symbolInfo.Shape = shape
+		logger.Log.Info("缠论分型", map[string]interface{}{"symbol": symbolInfo.Symbol, "cycle": cycle, "shape": shape, "rsi": rsiValue})
+		shouldNotify = true
+	}
+
+	// 3. RSI 超买超卖
+	if rsiValue >= float64(config.Cfg.Benchmark.Rsi.Top) || rsiValue <= float64(config.Cfg.Benchmark.Rsi.Low) {
+		shouldNotify = true
+	}
+
+	// 4. 量价异常 (背离、警惕、强势、恐慌、洗盘等)
+	vp := symbolInfo.VpSignal
+	if vp != "" && (contains(vp, "背离") || contains(vp, "强势") || contains(vp, "恐慌") || contains(vp, "洗盘") || contains(vp, "🔥")) {
+		shouldNotify = true
+	}
+
+	// 5. 一目均衡表 TK 交叉 / 云扭转
+	if ind.Ichimoku.TkCross != 0 || ind.Ichimoku.CloudTwist != 0 {
+		shouldNotify = true
+	}
+
+	// 6. VWAP 收复/跌破、触及外轨
+	if vwapSignalFmt(ind.Vwap) != "" {
+		shouldNotify = true
+	}
+
+	// 7. 超级趋势 / SAR 翻转
+	if ind.Supertrend.Flip != 0 || ind.Sar.Flip != 0 {
+		shouldNotify = true
+	}
+
+	// 8. MFI 超买超卖 / OBV 背离
+	if ind.VolumeFlow.MfiZone != 0 || ind.VolumeFlow.ObvDivergence != 0 {
+		shouldNotify = true
+	}
+
+	// 9. 震荡指标超买超卖 / KDJ 交叉
+	osc := ind.Oscillator
+	if osc.Kdj.Cross != 0 || osc.Kdj.Zone != 0 || osc.Stoch.Zone != 0 || osc.StochRsi.Zone != 0 || osc.Cci.Zone != 0 || osc.Wr.Zone != 0 {
+		shouldNotify = true
+	}
+
+	// 10. MACD 面积背离 (柱体首次缩短时提示) / 接近0轴
+	hist := ind.MacdHist
+	if (hist.AreaDivergence != 0 && hist.Trend == 2 && hist.Streak == 1) || hist.ZeroApproach != 0 {
+		shouldNotify = true
+	}
+
+	// 11. 离开缠论中枢
+	if ind.Chan.PivotBreak != 0 {
+		shouldNotify = true
+	}
+
+	// 12. SMC 结构突破 / 流动性扫荡
+	if ind.Smc.Signal != "" || ind.Smc.Sweep != 0 {
+		shouldNotify = true
+	}
 
-		// 3. RSI 超买超卖
-		if rsiValue >= float64(config.Cfg.Benchmark.Rsi.Top) || rsiValue <= float64(config.Cfg.Benchmark.Rsi.Low) {
+	// 13. 关键位置的K线形态
+	if candleSignalFmt(ind.Candles) != "" {
+		shouldNotify = true
+	}
+
+	// 14. 几何形态放量突破
+	for _, chart := range ind.Charts {
+		if chart.Breakout != 0 && chart.VolumeConfirmed {
 			shouldNotify = true
 		}
+	}
 
-		// 4. 量价异常 (背离、警惕、强势、恐慌、洗盘等)
-		vp := symbolInfo.VpSignal
-		if vp != "" && (contains(vp, "背离") || contains(vp, "强势") || contains(vp, "恐慌") || contains(vp, "洗盘") || contains(vp, "🔥")) {
+	// 15. 开盘位于价值区外 / 收复或跌破 POC
+	if ind.Profile.OpenOutside != 0 || ind.Profile.PocSignal != 0 {
+		shouldNotify = true
+	}
+
+	// 16. 收盘突破 R1/S1 及以上枢轴
+	if len(ind.Pivot.Breaks) > 0 {
+		shouldNotify = true
+	}
+
+	// 17. 进入斐波那契黄金口袋
+	if ind.Fib.EnterPocket {
+		shouldNotify = true
+	}
+
+	// 18. 连续跑赢/跑输基准达到指定K线数
+	for _, rs := range ind.Relative {
+		if r.rsParams.StreakAlert > 0 && (rs.Streak == r.rsParams.StreakAlert || rs.Streak == -r.rsParams.StreakAlert) {
 			shouldNotify = true
 		}
+	}
 
-		if shouldNotify {
-			Msg = alertMsgFmt(symbolInfo, cycle)
+	// 多周期共振得分不足时不推送
+	if shouldNotify && confluence.Enable {
+		if confluence.MinScore > 0 && ind.Confluence.Max > 0 && ind.Confluence.Percent() < confluence.MinScore {
+			shouldNotify = false
 		}
+	}
 
-		// 需要通知时入队，由 Worker 按订阅关系发送给对应用户
-		if Msg != "" {
-			notify.Push(symbol, cycle, Msg)
-		}
+	if shouldNotify {
+		Msg = alertMsgFmt(symbolInfo, cycle, ind)
 	}
 
+	// 需要通知时入队，由 Worker 按订阅关系发送给对应用户；开启聚类时待本轮结束后推送
+	if Msg != "" && r.cluster.Enable && !synthetic {
+		r.clusterAlerts = append(r.clusterAlerts, clusterAlert{Symbol: symbol, Bias: signalBias(symbolInfo.CrossType, ind, state), Msg: Msg})
+	} else if Msg != "" {
+		notify.Push(symbol, cycle, Msg)
+	}
 }
 
 // 将分析结果入库及更新
@@ -142,6 +482,11 @@ func saveSymbolRecord(symbolInfo *binanceFapi.SymbolInfo, cycle string, klines [
 		"vp_signal":         symbolInfo.VpSignal,
 		"change":            symbolInfo.Change,
 		"next_funding_time": symbolInfo.NextFundingTime,
+		"support":           symbolInfo.Support,
+		"resistance":        symbolInfo.Resistance,
+		"smc_signal":        symbolInfo.SMCSignal,
+		"fvg":               symbolInfo.Fvg,
+		"ob":                symbolInfo.Ob,
 	}
 	if klineIndex != 0 {
 		updates["cross_time"] = time.Unix(klines[klineIndex].CloseTime/1000, 0)
@@ -169,6 +514,11 @@ func saveSymbolRecord(symbolInfo *binanceFapi.SymbolInfo, cycle string, klines [
 		VpSignal:        symbolInfo.VpSignal,
 		Change:          symbolInfo.Change,
 		NextFundingTime: symbolInfo.NextFundingTime,
+		Support:         symbolInfo.Support,
+		Resistance:      symbolInfo.Resistance,
+		SMCSignal:       symbolInfo.SMCSignal,
+		Fvg:             symbolInfo.Fvg,
+		Ob:              symbolInfo.Ob,
 	}
 	if klineIndex != 0 {
 		rec.CrossTime = time.Unix(klines[klineIndex].CloseTime/1000, 0)
@@ -176,18 +526,360 @@ func saveSymbolRecord(symbolInfo *binanceFapi.SymbolInfo, cycle string, klines [
 	_ = database.DB.Create(&rec).Error
 }
 
+// 扩展指标结果入库及更新
+func saveIndicatorRecord(symbol, cycle string, ind *indicatorResult) {
+	vwapDay, _ := ind.Vwap.Band(VwapAnchorDay)
+	vwapWeek, _ := ind.Vwap.Band(VwapAnchorWeek)
+	vwapSwingHigh, _ := ind.Vwap.Band(VwapAnchorSwingHigh)
+	vwapSwingLow, _ := ind.Vwap.Band(VwapAnchorSwingLow)
+	vwapFunding, _ := ind.Vwap.Band(VwapAnchorFunding)
+	chart := primaryChartPattern(ind.Charts)
+	updates := map[string]interface{}{
+		// 一目均衡表
+		"tenkan":         ind.Ichimoku.Tenkan,
+		"kijun":          ind.Ichimoku.Kijun,
+		"senkou_a":       ind.Ichimoku.SenkouA,
+		"senkou_b":       ind.Ichimoku.SenkouB,
+		"chikou":         ind.Ichimoku.Chikou,
+		"cloud_position": ind.Ichimoku.CloudPosition,
+		"tk_cross":       ind.Ichimoku.TkCross,
+		"cloud_twist":    ind.Ichimoku.CloudTwist,
+		"chikou_confirm": ind.Ichimoku.ChikouConfirm,
+		// 锚定 VWAP
+		"vwap_day":        vwapDay.Vwap,
+		"vwap_day_std":    vwapDay.Std,
+		"vwap_week":       vwapWeek.Vwap,
+		"vwap_week_std":   vwapWeek.Std,
+		"vwap_swing_high": vwapSwingHigh.Vwap,
+		"vwap_swing_low":  vwapSwingLow.Vwap,
+		"vwap_funding":    vwapFunding.Vwap,
+		"vwap_signal":     vwapSignalFmt(ind.Vwap),
+		// 超级趋势 / SAR
+		"supertrend_trend": ind.Supertrend.Trend,
+		"supertrend_stop":  ind.Supertrend.Stop,
+		"supertrend_flip":  ind.Supertrend.Flip,
+		"sar_trend":        ind.Sar.Trend,
+		"sar":              ind.Sar.Stop,
+		"sar_flip":         ind.Sar.Flip,
+		// 量能指标
+		"obv":            ind.VolumeFlow.Obv,
+		"mfi":            ind.VolumeFlow.Mfi,
+		"cmf":            ind.VolumeFlow.Cmf,
+		"ad":             ind.VolumeFlow.Ad,
+		"mfi_zone":       ind.VolumeFlow.MfiZone,
+		"obv_divergence": ind.VolumeFlow.ObvDivergence,
+		// 震荡指标
+		"kdj_k":           ind.Oscillator.Kdj.K,
+		"kdj_d":           ind.Oscillator.Kdj.D,
+		"kdj_j":           ind.Oscillator.Kdj.J,
+		"kdj_cross":       ind.Oscillator.Kdj.Cross,
+		"kdj_zone":        ind.Oscillator.Kdj.Zone,
+		"stoch_k":         ind.Oscillator.Stoch.K,
+		"stoch_d":         ind.Oscillator.Stoch.D,
+		"stoch_cross":     ind.Oscillator.Stoch.Cross,
+		"stoch_zone":      ind.Oscillator.Stoch.Zone,
+		"stoch_rsi_k":     ind.Oscillator.StochRsi.K,
+		"stoch_rsi_d":     ind.Oscillator.StochRsi.D,
+		"stoch_rsi_cross": ind.Oscillator.StochRsi.Cross,
+		"stoch_rsi_zone":  ind.Oscillator.StochRsi.Zone,
+		"cci":             ind.Oscillator.Cci.K,
+		"cci_zone":        ind.Oscillator.Cci.Zone,
+		"wr":              ind.Oscillator.Wr.K,
+		"wr_zone":         ind.Oscillator.Wr.Zone,
+		// MACD 柱状图
+		"macd_hist":            ind.MacdHist.Hist,
+		"hist_trend":           ind.MacdHist.Trend,
+		"hist_streak":          ind.MacdHist.Streak,
+		"hist_area":            ind.MacdHist.Area,
+		"hist_prev_area":       ind.MacdHist.PrevArea,
+		"hist_area_divergence": ind.MacdHist.AreaDivergence,
+		"zero_approach":        ind.MacdHist.ZeroApproach,
+		// SMC
+		"smc_trend":   ind.Smc.Trend,
+		"fvg_type":    ind.Smc.Fvg.Type,
+		"fvg_low":     ind.Smc.Fvg.Low,
+		"fvg_high":    ind.Smc.Fvg.High,
+		"ob_type":     ind.Smc.Ob.Type,
+		"ob_low":      ind.Smc.Ob.Low,
+		"ob_high":     ind.Smc.Ob.High,
+		"equal_highs": ind.Smc.EqualHighs,
+		"equal_lows":  ind.Smc.EqualLows,
+		"sweep":       ind.Smc.Sweep,
+		"range_high":  ind.Smc.RangeHigh,
+		"range_low":   ind.Smc.RangeLow,
+		"smc_zone":    ind.Smc.Zone,
+		// K线形态
+		"candle_patterns": candlePatternsFmt(ind.Candles),
+		"candle_signal":   candleSignalFmt(ind.Candles),
+		// 几何形态
+		"chart_pattern":   chart.Name,
+		"chart_upper":     chart.Upper,
+		"chart_lower":     chart.Lower,
+		"chart_target":    chart.Target,
+		"chart_breakout":  chart.Breakout,
+		"chart_volume_ok": chart.VolumeConfirmed,
+		// 斐波那契回撤
+		"fib_direction":   ind.Fib.Direction,
+		"fib_start":       ind.Fib.Start,
+		"fib_end":         ind.Fib.End,
+		"fib_pocket_low":  ind.Fib.PocketLow,
+		"fib_pocket_high": ind.Fib.PocketHigh,
+		"fib_in_pocket":   ind.Fib.InPocket,
+		// 多周期共振
+		"confluence_bias":  ind.Confluence.Bias,
+		"confluence_score": ind.Confluence.Score,
+		"confluence_max":   ind.Confluence.Max,
+	}
+	result := database.DB.Model(&store.IndicatorRecord{}).
+		Where("symbol = ? AND cycle = ?", symbol, cycle).
+		Updates(updates)
+	if result.Error != nil || result.RowsAffected != 0 {
+		return
+	}
+
+	// 首次写入
+	updates["symbol"] = symbol
+	updates["cycle"] = cycle
+	_ = database.DB.Model(&store.IndicatorRecord{}).Create(updates).Error
+}
+
+// 笔/线段端点序列化
+func chanVerticesJSON(klines []binanceFapi.KLine, strokes []chanStroke) string {
+	type vertex struct {
+		Time  int64   `json:"t"`
+		Price float64 `json:"p"`
+		Type  int     `json:"type"`
+	}
+	if len(strokes) == 0 {
+		return "[]"
+	}
+	vertices := []vertex{{klines[strokes[0].Start.Index].OpenTime, strokes[0].Start.Price, strokes[0].Start.Type}}
+	for _, s := range strokes {
+		vertices = append(vertices, vertex{klines[s.End.Index].OpenTime, s.End.Price, s.End.Type})
+	}
+	data, _ := json.Marshal(vertices)
+	return string(data)
+}
+
+// 缠论结构入库及更新
+func saveChanRecord(symbol, cycle string, klines []binanceFapi.KLine, res ChanResult) {
+	updates := map[string]interface{}{
+		"stroke_count":     len(res.Strokes),
+		"last_stroke_dir":  0,
+		"segment_count":    len(res.Segments),
+		"last_segment_dir": 0,
+		"pivot_count":      len(res.Pivots),
+		"pivot_high":       0.0,
+		"pivot_low":        0.0,
+		"pivot_gg":         0.0,
+		"pivot_dd":         0.0,
+		"pivot_start":      time.Time{},
+		"pivot_end":        time.Time{},
+		"pivot_break":      res.PivotBreak,
+		"strokes":          chanVerticesJSON(klines, res.Strokes),
+		"segments":         chanVerticesJSON(klines, res.Segments),
+	}
+	if len(res.Strokes) > 0 {
+		updates["last_stroke_dir"] = res.Strokes[len(res.Strokes)-1].Direction
+	}
+	if len(res.Segments) > 0 {
+		updates["last_segment_dir"] = res.Segments[len(res.Segments)-1].Direction
+	}
+	if len(res.Pivots) > 0 {
+		pivot := res.Pivots[len(res.Pivots)-1]
+		updates["pivot_high"] = pivot.ZG
+		updates["pivot_low"] = pivot.ZD
+		updates["pivot_gg"] = pivot.GG
+		updates["pivot_dd"] = pivot.DD
+		updates["pivot_start"] = time.Unix(klines[res.Strokes[pivot.StrokeStart].Start.Index].OpenTime/1000, 0)
+		updates["pivot_end"] = time.Unix(klines[res.Strokes[pivot.StrokeEnd].End.Index].OpenTime/1000, 0)
+	}
+
+	result := database.DB.Model(&store.ChanRecord{}).
+		Where("symbol = ? AND cycle = ?", symbol, cycle).
+		Updates(updates)
+	if result.Error != nil || result.RowsAffected != 0 {
+		return
+	}
+
+	// 首次写入
+	updates["symbol"] = symbol
+	updates["cycle"] = cycle
+	_ = database.DB.Model(&store.ChanRecord{}).Create(updates).Error
+}
+
+// 成交量分布入库及更新
+func saveVolumeProfiles(symbol, cycle string, res VolumeProfileResult) {
+	for _, vp := range res.Profiles {
+		bins := make([][2]float64, len(vp.Bins))
+		for b, vol := range vp.Bins {
+			bins[b] = [2]float64{vp.binPrice(b), vol}
+		}
+		binsJSON, _ := json.Marshal(bins)
+		lvnsJSON, _ := json.Marshal(vp.Lvns)
+		if vp.Lvns == nil {
+			lvnsJSON = []byte("[]")
+		}
+
+		updates := map[string]interface{}{
+			"start_time": time.Unix(vp.StartTime/1000, 0),
+			"end_time":   time.Unix(vp.EndTime/1000, 0),
+			"poc":        vp.Poc,
+			"vah":        vp.Vah,
+			"val":        vp.Val,
+			"lvns":       string(lvnsJSON),
+			"bins":       string(binsJSON),
+		}
+		result := database.DB.Model(&store.VolumeProfileRecord{}).
+			Where("symbol = ? AND cycle = ? AND session = ?", symbol, cycle, vp.Session).
+			Updates(updates)
+		if result.Error != nil || result.RowsAffected != 0 {
+			continue
+		}
+
+		// 首次写入
+		updates["symbol"] = symbol
+		updates["cycle"] = cycle
+		updates["session"] = vp.Session
+		_ = database.DB.Model(&store.VolumeProfileRecord{}).Create(updates).Error
+	}
+}
+
+// 相对强弱入库及更新
+func saveRelativeStrength(symbol, cycle string, list []RelativeStrength) {
+	for _, rs := range list {
+		updates := map[string]interface{}{
+			"rs":           rs.Rs,
+			"rs_change":    rs.RsChange,
+			"return":       rs.Return,
+			"bench_return": rs.BenchReturn,
+			"beta":         rs.Beta,
+			"correlation":  rs.Correlation,
+			"rs_rsi":       rs.RsRsi,
+			"rs_macd_hist": rs.RsMacdHist,
+			"rs_cross":     rs.RsCross,
+			"streak":       rs.Streak,
+		}
+		result := database.DB.Model(&store.RelativeStrengthRecord{}).
+			Where("symbol = ? AND cycle = ? AND benchmark = ?", symbol, cycle, rs.Benchmark).
+			Updates(updates)
+		if result.Error != nil || result.RowsAffected != 0 {
+			continue
+		}
+
+		// 首次写入
+		updates["symbol"] = symbol
+		updates["cycle"] = cycle
+		updates["benchmark"] = rs.Benchmark
+		_ = database.DB.Model(&store.RelativeStrengthRecord{}).Create(updates).Error
+	}
+}
+
+// 枢轴点入库及更新
+func savePivotPoints(symbol string, res PivotResult) {
+	for _, set := range res.Sets {
+		updates := map[string]interface{}{
+			"period_start": time.Unix(set.PeriodStart/1000, 0),
+			"r4":           0.0,
+			"s4":           0.0,
+		}
+		for _, level := range set.Levels {
+			switch level.Name {
+			case "P", "R1", "R2", "R3", "R4", "S1", "S2", "S3", "S4":
+				updates[strings.ToLower(level.Name)] = level.Price
+			}
+		}
+		result := database.DB.Model(&store.PivotPointRecord{}).
+			Where("symbol = ? AND timeframe = ? AND method = ?", symbol, set.Timeframe, set.Method).
+			Updates(updates)
+		if result.Error != nil || result.RowsAffected != 0 {
+			continue
+		}
+
+		// 首次写入
+		updates["symbol"] = symbol
+		updates["timeframe"] = set.Timeframe
+		updates["method"] = set.Method
+		_ = database.DB.Model(&store.PivotPointRecord{}).Create(updates).Error
+	}
+}
+
+// 缠论买卖点入库，已存在相同买卖点时返回 false
+func saveChanPoint(symbol, cycle string, klines []binanceFapi.KLine, res ChanResult, point ChanPoint) bool {
+	pointTime := time.Unix(klines[res.Strokes[point.Stroke].End.Index].OpenTime/1000, 0)
+	var count int64
+	err := database.DB.Model(&store.ChanPointRecord{}).
+		Where("symbol = ? AND cycle = ? AND side = ? AND class = ? AND point_time = ?", symbol, cycle, point.Side, point.Class, pointTime).
+		Count(&count).Error
+	if err != nil || count > 0 {
+		return false
+	}
+
+	rec := store.ChanPointRecord{
+		Symbol:    symbol,
+		Cycle:     cycle,
+		Side:      point.Side,
+		Class:     point.Class,
+		Price:     point.Price,
+		PointTime: pointTime,
+		PivotHigh: point.Pivot.ZG,
+		PivotLow:  point.Pivot.ZD,
+		Area:      point.Area,
+		PrevArea:  point.PrevArea,
+	}
+	if err := database.DB.Create(&rec).Error; err != nil {
+		logger.Log.Error("缠论买卖点入库失败", map[string]interface{}{"symbol": symbol, "cycle": cycle, "err": err.Error()})
+		return false
+	}
+	return true
+}
+
+// 背离入库，已存在相同背离时返回 false
+func saveDivergence(symbol, cycle string, klines []binanceFapi.KLine, d Divergence) bool {
+	toTime := time.Unix(klines[d.To.Index].OpenTime/1000, 0)
+	var count int64
+	err := database.DB.Model(&store.DivergenceRecord{}).
+		Where("symbol = ? AND cycle = ? AND indicator = ? AND type = ? AND to_time = ?", symbol, cycle, d.Indicator, d.Type, toTime).
+		Count(&count).Error
+	if err != nil || count > 0 {
+		return false
+	}
+
+	rec := store.DivergenceRecord{
+		Symbol:    symbol,
+		Cycle:     cycle,
+		Indicator: d.Indicator,
+		Type:      d.Type,
+		FromTime:  time.Unix(klines[d.From.Index].OpenTime/1000, 0),
+		FromPrice: d.From.Price,
+		FromValue: d.FromValue,
+		ToTime:    toTime,
+		ToPrice:   d.To.Price,
+		ToValue:   d.ToValue,
+	}
+	if err := database.DB.Create(&rec).Error; err != nil {
+		logger.Log.Error("背离入库失败", map[string]interface{}{"symbol": symbol, "cycle": cycle, "err": err.Error()})
+		return false
+	}
+	return true
+}
+
 // ticker
 func MacdTicker(ctx context.Context, cycle string) {
-	duration := CycleDurationFmt(cycle)
+	spec, err := parseCycle(cycle)
+	if err != nil {
+		logger.Log.Error("周期配置无效", map[string]interface{}{"cycle": cycle, "err": err.Error()})
+		return
+	}
 
 	// 如果是开发模式，立即执行第一次
 	if config.Cfg.Mode == "dev" {
 		logger.Log.Info("开发模式: 立即开始首次执行", map[string]interface{}{"cycle": cycle})
 		go Start(ctx, cycle)
 	} else {
-		// 计算距离下一次整点的时间
+		// 计算距离下一根K线开盘的时间 (自定义周期按其时区边界对齐)
 		now := time.Now()
-		nextTick := now.Truncate(duration).Add(duration)
+		nextTick := spec.nextStart(now)
 		waitTime := nextTick.Sub(now)
 
 		logger.Log.Info("任务将在后开始", map[string]interface{}{"cycle": cycle, "time": nextTick.Format("15:04:05"), "wait": waitTime})
@@ -205,17 +897,16 @@ func MacdTicker(ctx context.Context, cycle string) {
 		}
 	}
 
-	// 启动周期性 Ticker
-	ticker := time.NewTicker(duration)
-	defer ticker.Stop()
-
+	// 每次按下一根K线开盘时间重新计时，月线等不等长周期也能对齐
 	for {
+		timer := time.NewTimer(time.Until(spec.nextStart(time.Now())))
 		select {
 		case <-ctx.Done():
+			timer.Stop()
 			logger.Log.Info("周期任务收到退出信号", map[string]interface{}{"cycle": cycle})
 			return
 
-		case <-ticker.C:
+		case <-timer.C:
 			logger.Log.Info("周期性任务执行中...", map[string]interface{}{"cycle": cycle})
 			go Start(ctx, cycle)
 		}
@@ -0,0 +1,273 @@
+package calculate
+
+import (
+	"IndicatorTask/binanceFapi"
+	"IndicatorTask/config"
+)
+
+// OscillatorLine 震荡指标最新值及状态，单线指标 (CCI / 威廉) 仅使用 K
+type OscillatorLine struct {
+	K       float64
+	D       float64
+	J       float64
+	Cross   int  // 0: 无, 1: K上穿D, 2: K下穿D
+	Zone    int  // 0: 正常, 1: 超买 (KDJ 为 J 值过高), 2: 超卖 (KDJ 为 J 值过低)
+	Enabled bool // 已启用且K线数足够计算 (0 为有效极值，不能以数值判断是否输出)
+}
+
+// OscillatorResult 震荡指标组
+type OscillatorResult struct {
+	Kdj      OscillatorLine
+	Stoch    OscillatorLine
+	StochRsi OscillatorLine
+	Cci      OscillatorLine
+	Wr       OscillatorLine
+}
+
+// 简单移动平均序列，前 period-1 个位置为已有数据的均值
+func calculateSMA(values []float64, period int) []float64 {
+	sma := make([]float64, len(values))
+	var sum float64
+	for i, v := range values {
+		sum += v
+		if i >= period {
+			sum -= values[i-period]
+			sma[i] = sum / float64(period)
+		} else {
+			sma[i] = sum / float64(i+1)
+		}
+	}
+	return sma
+}
+
+// 区间未成熟随机值 RSV 序列: (C-LLV)/(HHV-LLV)*100
+func calculateRSV(closes, highs, lows []float64, period int) []float64 {
+	rsv := make([]float64, len(closes))
+	for i := range closes {
+		start := i - period + 1
+		if start < 0 {
+			start = 0
+		}
+		hh, ll := highs[start], lows[start]
+		for j := start + 1; j <= i; j++ {
+			hh = max(hh, highs[j])
+			ll = min(ll, lows[j])
+		}
+		if hh == ll {
+			rsv[i] = 50
+		} else {
+			rsv[i] = (closes[i] - ll) / (hh - ll) * 100
+		}
+	}
+	return rsv
+}
+
+// K/D 交叉
+func kdCross(k, d []float64) int {
+	n := len(k)
+	if n < 2 {
+		return 0
+	}
+	if k[n-2] <= d[n-2] && k[n-1] > d[n-1] {
+		return 1
+	}
+	if k[n-2] >= d[n-2] && k[n-1] < d[n-1] {
+		return 2
+	}
+	return 0
+}
+
+// 超买超卖区域
+func zoneOf(value float64, top, low int) int {
+	if value >= float64(top) {
+		return 1
+	}
+	if value <= float64(low) {
+		return 2
+	}
+	return 0
+}
+
+func klineSeries(klines []binanceFapi.KLine) ([]float64, []float64, []float64) {
+	closes := make([]float64, len(klines))
+	highs := make([]float64, len(klines))
+	lows := make([]float64, len(klines))
+	for i, k := range klines {
+		closes[i], highs[i], lows[i] = k.Close, k.High, k.Low
+	}
+	return closes, highs, lows
+}
+
+// 计算 KDJ (国内常用 SMA 平滑版本)
+func calculateKDJ(klines []binanceFapi.KLine, params config.Kdj) OscillatorLine {
+	if params.N <= 0 {
+		params.N = 9
+	}
+	if params.M1 <= 0 {
+		params.M1 = 3
+	}
+	if params.M2 <= 0 {
+		params.M2 = 3
+	}
+	if params.JTop == 0 && params.JLow == 0 {
+		params.JTop, params.JLow = 100, 0
+	}
+	n := len(klines)
+	if n < params.N+1 {
+		return OscillatorLine{}
+	}
+
+	closes, highs, lows := klineSeries(klines)
+	rsv := calculateRSV(closes, highs, lows, params.N)
+	k := make([]float64, n)
+	d := make([]float64, n)
+	prevK, prevD := 50.0, 50.0
+	for i := range rsv {
+		k[i] = (prevK*float64(params.M1-1) + rsv[i]) / float64(params.M1)
+		d[i] = (prevD*float64(params.M2-1) + k[i]) / float64(params.M2)
+		prevK, prevD = k[i], d[i]
+	}
+
+	res := OscillatorLine{K: k[n-1], D: d[n-1], Enabled: true}
+	res.J = 3*res.K - 2*res.D
+	res.Cross = kdCross(k, d)
+	res.Zone = zoneOf(res.J, params.JTop, params.JLow)
+	return res
+}
+
+// 对任意序列计算随机指标 (Stochastic 为价格, Stochastic RSI 为 RSI 序列)
+func stochOf(closes, highs, lows []float64, params config.Stoch) OscillatorLine {
+	n := len(closes)
+	if n < params.Period+params.SmoothK+params.SmoothD {
+		return OscillatorLine{}
+	}
+	rsv := calculateRSV(closes, highs, lows, params.Period)
+	k := calculateSMA(rsv, params.SmoothK)
+	d := calculateSMA(k, params.SmoothD)
+
+	res := OscillatorLine{K: k[n-1], D: d[n-1], Enabled: true}
+	res.Cross = kdCross(k, d)
+	res.Zone = zoneOf(res.K, params.Top, params.Low)
+	return res
+}
+
+func stochDefaults(params config.Stoch) config.Stoch {
+	if params.Period <= 0 {
+		params.Period = 14
+	}
+	if params.SmoothK <= 0 {
+		params.SmoothK = 3
+	}
+	if params.SmoothD <= 0 {
+		params.SmoothD = 3
+	}
+	if params.Top == 0 && params.Low == 0 {
+		params.Top, params.Low = 80, 20
+	}
+	return params
+}
+
+// 计算随机指标
+func calculateStoch(klines []binanceFapi.KLine, params config.Stoch) OscillatorLine {
+	closes, highs, lows := klineSeries(klines)
+	return stochOf(closes, highs, lows, stochDefaults(params))
+}
+
+// 计算随机 RSI
+func calculateStochRsi(klines []binanceFapi.KLine, params config.Stoch) OscillatorLine {
+	params = stochDefaults(params)
+	rsiPeriod := config.Cfg.Benchmark.Rsi.Period
+	if rsiPeriod <= 0 {
+		rsiPeriod = 14
+	}
+	closes := binanceFapi.ClosePrice(klines)
+	if len(closes) <= rsiPeriod {
+		return OscillatorLine{}
+	}
+	// 去掉 RSI 尚未成熟的部分
+	rsi := calculateRsiSeries(closes, rsiPeriod)[rsiPeriod:]
+	return stochOf(rsi, rsi, rsi, params)
+}
+
+// 计算 CCI
+func calculateCCI(klines []binanceFapi.KLine, params config.Band) OscillatorLine {
+	if params.Period <= 0 {
+		params.Period = 20
+	}
+	if params.Top == 0 && params.Low == 0 {
+		params.Top, params.Low = 100, -100
+	}
+	n := len(klines)
+	if n < params.Period {
+		return OscillatorLine{}
+	}
+
+	tp := make([]float64, params.Period)
+	var sum float64
+	for i := range tp {
+		k := klines[n-params.Period+i]
+		tp[i] = (k.High + k.Low + k.Close) / 3
+		sum += tp[i]
+	}
+	mean := sum / float64(params.Period)
+	var dev float64
+	for _, v := range tp {
+		dev += abs(v - mean)
+	}
+	dev /= float64(params.Period)
+
+	res := OscillatorLine{Enabled: true}
+	if dev > 0 {
+		res.K = (tp[len(tp)-1] - mean) / (0.015 * dev)
+	}
+	res.Zone = zoneOf(res.K, params.Top, params.Low)
+	return res
+}
+
+// 计算威廉指标 %R (-100 ~ 0)
+func calculateWR(klines []binanceFapi.KLine, params config.Band) OscillatorLine {
+	if params.Period <= 0 {
+		params.Period = 14
+	}
+	if params.Top == 0 && params.Low == 0 {
+		params.Top, params.Low = -20, -80
+	}
+	n := len(klines)
+	if n < params.Period {
+		return OscillatorLine{}
+	}
+
+	hh, ll := klines[n-params.Period].High, klines[n-params.Period].Low
+	for i := n - params.Period + 1; i < n; i++ {
+		hh = max(hh, klines[i].High)
+		ll = min(ll, klines[i].Low)
+	}
+
+	res := OscillatorLine{K: -50, Enabled: true}
+	if hh > ll {
+		res.K = (hh - klines[n-1].Close) / (hh - ll) * -100
+	}
+	res.Zone = zoneOf(res.K, params.Top, params.Low)
+	return res
+}
+
+// 按配置计算启用的震荡指标
+func calculateOscillators(klines []binanceFapi.KLine, params config.Oscillator) OscillatorResult {
+	res := OscillatorResult{}
+	if params.Kdj.Enable {
+		res.Kdj = calculateKDJ(klines, params.Kdj)
+	}
+	if params.Stoch.Enable {
+		res.Stoch = calculateStoch(klines, params.Stoch)
+	}
+	if params.StochRsi.Enable {
+		res.StochRsi = calculateStochRsi(klines, params.StochRsi)
+	}
+	if params.Cci.Enable {
+		res.Cci = calculateCCI(klines, params.Cci)
+	}
+	if params.Wr.Enable {
+		res.Wr = calculateWR(klines, params.Wr)
+	}
+	return res
+}
@@ -0,0 +1,266 @@
+package calculate
+
+import (
+	"IndicatorTask/config"
+	"IndicatorTask/store"
+	"IndicatorTask/utils/logger"
+	"IndicatorTask/utils/notify"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/cryptoSelect/public/database"
+)
+
+// 配对价差计算方式
+const (
+	PairRatio  = "ratio"  // 对数价格比
+	PairSpread = "spread" // 对冲比例调整后的对数价差
+)
+
+// 5% 临界值：spread 模式对冲比例为估计值，使用 Engle-Granger 两变量临界值；
+// ratio 模式对冲比例固定为 1，等价于对价差做 Dickey-Fuller 检验 (含常数项)
+const (
+	egCritical5 = -3.34
+	dfCritical5 = -2.86
+)
+
+// PairResult 配对价差统计
+type PairResult struct {
+	Name         string // 如 ETHUSDT/BTCUSDT
+	Mode         string
+	HedgeRatio   float64 // log(A) = α + β·log(B) 中的 β，ratio 模式为 1
+	Spread       float64 // 最新价差
+	ZScore       float64
+	PrevZScore   float64
+	HalfLife     float64 // 均值回归半衰期 (K线数)，不回归时为 0
+	AdfStat      float64 // 价差 Dickey-Fuller 统计量
+	Cointegrated bool
+	Signal       int // 0: 无, 1: 开仓做多价差 (z 跌破 -Entry), 2: 开仓做空价差 (z 突破 Entry), 3: 持仓中 z 回到 Exit 以内平仓
+	Position     int // 本轮后的持仓：0: 空仓, 1: 做多价差, 2: 做空价差
+}
+
+// 最小二乘 y = a + b·x
+func linearRegression(x, y []float64) (float64, float64) {
+	n := float64(len(x))
+	var sumX, sumY, sumXY, sumXX float64
+	for i := range x {
+		sumX += x[i]
+		sumY += y[i]
+		sumXY += x[i] * y[i]
+		sumXX += x[i] * x[i]
+	}
+	den := n*sumXX - sumX*sumX
+	if den == 0 {
+		return sumY / n, 0
+	}
+	b := (n*sumXY - sumX*sumY) / den
+	return (sumY - b*sumX) / n, b
+}
+
+// 均值与标准差
+func meanStd(values []float64) (float64, float64) {
+	var mean float64
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(variance / float64(len(values)))
+}
+
+// 对价差做 Δs(t) = c + λ·s(t-1) 回归，返回 λ 与其 t 统计量 (Dickey-Fuller)
+func dickeyFuller(spread []float64) (float64, float64) {
+	n := len(spread) - 1
+	if n < 3 {
+		return 0, 0
+	}
+	lagged, diff := make([]float64, n), make([]float64, n)
+	for i := 0; i < n; i++ {
+		lagged[i] = spread[i]
+		diff[i] = spread[i+1] - spread[i]
+	}
+	c, lambda := linearRegression(lagged, diff)
+	meanLag, _ := meanStd(lagged)
+	var sse, sxx float64
+	for i := 0; i < n; i++ {
+		e := diff[i] - c - lambda*lagged[i]
+		sse += e * e
+		sxx += (lagged[i] - meanLag) * (lagged[i] - meanLag)
+	}
+	if sxx == 0 || n <= 2 {
+		return lambda, 0
+	}
+	se := math.Sqrt(sse / float64(n-2) / sxx)
+	if se == 0 {
+		return lambda, 0
+	}
+	return lambda, lambda / se
+}
+
+// 计算配对价差、z-score、半衰期与协整检验，position 为上一轮的持仓，平仓信号只在持仓时发出
+func calculatePair(a, b []float64, pair config.Pair, position int) (PairResult, bool) {
+	if pair.Window <= 0 {
+		pair.Window = 100
+	}
+	if pair.Entry <= 0 {
+		pair.Entry = 2
+	}
+	if pair.Exit <= 0 {
+		pair.Exit = 0.5
+	}
+	res := PairResult{Name: pairName(pair), Mode: pair.Mode, HedgeRatio: 1, Position: position}
+	if res.Mode != PairSpread {
+		res.Mode = PairRatio
+	}
+	n := len(a)
+	if n < pair.Window+1 {
+		return res, false
+	}
+	logA, logB := make([]float64, n), make([]float64, n)
+	for i := range a {
+		logA[i], logB[i] = math.Log(a[i]), math.Log(b[i])
+	}
+
+	// 对冲比例与价差，均以最近 Window 根K线估计
+	from := n - pair.Window
+	alpha := 0.0
+	if res.Mode == PairSpread {
+		alpha, res.HedgeRatio = linearRegression(logB[from:], logA[from:])
+	}
+	spread := make([]float64, n)
+	for i := range spread {
+		spread[i] = logA[i] - res.HedgeRatio*logB[i] - alpha
+	}
+	res.Spread = spread[n-1]
+
+	// 滚动 z-score (最新及前一根K线)
+	zscore := func(end int) float64 {
+		mean, std := meanStd(spread[end-pair.Window+1 : end+1])
+		if std == 0 {
+			return 0
+		}
+		return (spread[end] - mean) / std
+	}
+	res.ZScore = zscore(n - 1)
+	if n-2 >= pair.Window-1 {
+		res.PrevZScore = zscore(n - 2)
+	}
+
+	lambda, stat := dickeyFuller(spread[from:])
+	res.AdfStat = stat
+	critical := dfCritical5
+	if res.Mode == PairSpread {
+		critical = egCritical5
+	}
+	res.Cointegrated = stat < critical
+	if lambda < 0 && lambda > -1 {
+		res.HalfLife = -math.Ln2 / math.Log(1+lambda)
+	}
+
+	z, prev := res.ZScore, res.PrevZScore
+	switch {
+	case z >= pair.Entry && prev < pair.Entry:
+		res.Signal, res.Position = 2, 2
+	case z <= -pair.Entry && prev > -pair.Entry:
+		res.Signal, res.Position = 1, 1
+	case position != 0 && math.Abs(z) <= pair.Exit:
+		res.Signal, res.Position = 3, 0
+	}
+	return res, true
+}
+
+func pairName(pair config.Pair) string {
+	return strings.ToUpper(pair.Base) + "/" + strings.ToUpper(pair.Quote)
+}
+
+// 计算全部配对，入库并在 z-score 穿越开仓/平仓阈值时提醒 (订阅 symbol 为 BASE/QUOTE)
+func (r *cycleRun) analyzePairs() {
+	for _, pair := range config.Cfg.Pairs {
+		if pair.Base == "" || pair.Quote == "" {
+			continue
+		}
+		klinesA, errA := r.cachedKlines(strings.ToUpper(pair.Base))
+		klinesB, errB := r.cachedKlines(strings.ToUpper(pair.Quote))
+		if errA != nil || errB != nil {
+			logger.Log.Error("获取配对K线失败", map[string]interface{}{"pair": pairName(pair), "errA": errA, "errB": errB})
+			continue
+		}
+		a, b := alignCloses(closedKlines(klinesA), closedKlines(klinesB))
+		res, ok := calculatePair(a, b, pair, pairPosition(pairName(pair), r.cycle))
+		if !ok {
+			continue
+		}
+		savePairRecord(r.cycle, pair, res)
+		if res.Signal != 0 {
+			notify.PushKind(res.Name, r.cycle, notify.KindPair, pairMsgFmt(r.cycle, res))
+		}
+	}
+}
+
+// 上一轮记录的持仓，无记录或查询失败视为空仓
+func pairPosition(name, cycle string) int {
+	var rec store.PairRecord
+	if err := database.DB.Select("position").Where("pair = ? AND cycle = ?", name, cycle).Limit(1).Find(&rec).Error; err != nil {
+		return 0
+	}
+	return rec.Position
+}
+
+// 配对统计入库及更新
+func savePairRecord(cycle string, pair config.Pair, res PairResult) {
+	updates := map[string]interface{}{
+		"mode":         res.Mode,
+		"hedge_ratio":  res.HedgeRatio,
+		"spread":       res.Spread,
+		"z_score":      res.ZScore,
+		"half_life":    res.HalfLife,
+		"adf_stat":     res.AdfStat,
+		"cointegrated": res.Cointegrated,
+		"signal":       res.Signal,
+		"position":     res.Position,
+	}
+	result := database.DB.Model(&store.PairRecord{}).
+		Where("pair = ? AND cycle = ?", res.Name, cycle).
+		Updates(updates)
+	if result.Error != nil || result.RowsAffected != 0 {
+		return
+	}
+
+	// 首次写入
+	updates["pair"] = res.Name
+	updates["cycle"] = cycle
+	updates["base"] = strings.ToUpper(pair.Base)
+	updates["quote"] = strings.ToUpper(pair.Quote)
+	_ = database.DB.Model(&store.PairRecord{}).Create(updates).Error
+}
+
+// 配对提醒
+func pairMsgFmt(cycle string, res PairResult) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("     ---- 【  配对 %s %s  】 ---- \n", res.Name, cycle))
+	switch res.Signal {
+	case 1:
+		builder.WriteString("信号: 价差偏低，做多价差\n")
+	case 2:
+		builder.WriteString("信号: 价差偏高，做空价差\n")
+	case 3:
+		builder.WriteString("信号: 价差回归，平仓\n")
+	}
+	builder.WriteString(fmt.Sprintf("Z-Score: %.2f (前值 %.2f)\n", res.ZScore, res.PrevZScore))
+	if res.Mode == PairSpread {
+		builder.WriteString(fmt.Sprintf("对冲比例: %.4f\n", res.HedgeRatio))
+	}
+	if res.HalfLife > 0 {
+		builder.WriteString(fmt.Sprintf("半衰期: %.1f 根K线\n", res.HalfLife))
+	}
+	cointegrated := "否"
+	if res.Cointegrated {
+		cointegrated = "是"
+	}
+	builder.WriteString(fmt.Sprintf("协整: %s (ADF %.2f)\n", cointegrated, res.AdfStat))
+	return builder.String()
+}
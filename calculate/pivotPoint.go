@@ -0,0 +1,168 @@
+package calculate
+
+import (
+	"IndicatorTask/binanceFapi"
+	"IndicatorTask/config"
+	"sync"
+	"time"
+)
+
+// 枢轴点计算方法
+const (
+	PivotClassic   = "classic"
+	PivotFibonacci = "fibonacci"
+	PivotCamarilla = "camarilla"
+	PivotWoodie    = "woodie"
+)
+
+// PivotLevel 单个枢轴价位
+type PivotLevel struct {
+	Name  string // P / R1-R4 / S1-S4
+	Price float64
+}
+
+// PivotSet 某一高级别周期、某一方法的枢轴点
+type PivotSet struct {
+	Timeframe   string // 1d / 1w / 1M
+	Method      string
+	PeriodStart int64 // 用于计算的上一根高级别K线开盘时间 (ms)
+	Levels      []PivotLevel
+}
+
+// PivotResult 枢轴点及与最新价格的关系
+type PivotResult struct {
+	Sets   []PivotSet
+	Above  PivotRef   // 价格上方最近的枢轴
+	Below  PivotRef   // 价格下方最近的枢轴
+	Breaks []PivotRef // 最新K线收盘突破的 R1 及以上 / 跌破的 S1 及以下
+}
+
+// PivotRef 引用某一组枢轴中的价位
+type PivotRef struct {
+	Timeframe string
+	Method    string
+	Level     PivotLevel
+}
+
+// 按方法计算枢轴点，h/l/c 为上一根高级别K线的高低收
+func pivotLevels(method string, h, l, c float64) []PivotLevel {
+	r := h - l
+	p := (h + l + c) / 3
+	switch method {
+	case PivotFibonacci:
+		return []PivotLevel{
+			{"P", p},
+			{"R1", p + 0.382*r}, {"R2", p + 0.618*r}, {"R3", p + r},
+			{"S1", p - 0.382*r}, {"S2", p - 0.618*r}, {"S3", p - r},
+		}
+	case PivotCamarilla:
+		return []PivotLevel{
+			{"P", p},
+			{"R1", c + r*1.1/12}, {"R2", c + r*1.1/6}, {"R3", c + r*1.1/4}, {"R4", c + r*1.1/2},
+			{"S1", c - r*1.1/12}, {"S2", c - r*1.1/6}, {"S3", c - r*1.1/4}, {"S4", c - r*1.1/2},
+		}
+	case PivotWoodie:
+		p = (h + l + 2*c) / 4
+		return []PivotLevel{
+			{"P", p},
+			{"R1", 2*p - l}, {"R2", p + r}, {"R3", h + 2*(p-l)},
+			{"S1", 2*p - h}, {"S2", p - r}, {"S3", l - 2*(h-p)},
+		}
+	}
+	return []PivotLevel{
+		{"P", p},
+		{"R1", 2*p - l}, {"R2", p + r}, {"R3", h + 2*(p-l)},
+		{"S1", 2*p - h}, {"S2", p - r}, {"S3", l - 2*(h-p)},
+	}
+}
+
+// 高级别K线缓存：同一 symbol+周期在当前高级别K线收盘前无需重复请求
+type pivotCacheEntry struct {
+	prev      binanceFapi.KLine
+	expiresAt int64 // 当前高级别K线收盘时间 (ms)
+}
+
+var (
+	pivotCache   = make(map[string]pivotCacheEntry)
+	pivotCacheMu sync.Mutex
+)
+
+// 获取上一根已收盘的高级别K线
+func previousHigherKline(symbol, timeframe string) (binanceFapi.KLine, bool) {
+	key := symbol + "|" + timeframe
+	now := time.Now().UnixMilli()
+
+	pivotCacheMu.Lock()
+	entry, ok := pivotCache[key]
+	pivotCacheMu.Unlock()
+	if ok && now <= entry.expiresAt {
+		return entry.prev, true
+	}
+
+	klines, err := getCycleKlinesLimit(symbol, timeframe, 2)
+	if err != nil || len(klines) < 2 {
+		return binanceFapi.KLine{}, false
+	}
+	entry = pivotCacheEntry{prev: klines[0], expiresAt: klines[1].CloseTime}
+
+	pivotCacheMu.Lock()
+	pivotCache[key] = entry
+	pivotCacheMu.Unlock()
+	return entry.prev, true
+}
+
+// 计算各高级别周期的枢轴点，并标注最近上下方枢轴与突破
+func calculatePivotPoints(symbol string, klines []binanceFapi.KLine, params config.PivotPoint) PivotResult {
+	res := PivotResult{}
+	n := len(klines)
+	if n < 2 {
+		return res
+	}
+	timeframes := params.Timeframes
+	if len(timeframes) == 0 {
+		timeframes = []string{"1d", "1w", "1M"}
+	}
+	methods := params.Methods
+	if len(methods) == 0 {
+		methods = []string{PivotClassic, PivotFibonacci, PivotCamarilla, PivotWoodie}
+	}
+
+	for _, tf := range timeframes {
+		prev, ok := previousHigherKline(symbol, tf)
+		if !ok {
+			continue
+		}
+		for _, method := range methods {
+			res.Sets = append(res.Sets, PivotSet{
+				Timeframe:   tf,
+				Method:      method,
+				PeriodStart: prev.OpenTime,
+				Levels:      pivotLevels(method, prev.High, prev.Low, prev.Close),
+			})
+		}
+	}
+
+	curr, prevClose := klines[n-1].Close, klines[n-2].Close
+	for _, set := range res.Sets {
+		for _, level := range set.Levels {
+			ref := PivotRef{Timeframe: set.Timeframe, Method: set.Method, Level: level}
+			if level.Price > curr && (res.Above.Level.Name == "" || level.Price < res.Above.Level.Price) {
+				res.Above = ref
+			}
+			if level.Price < curr && (res.Below.Level.Name == "" || level.Price > res.Below.Level.Price) {
+				res.Below = ref
+			}
+			switch level.Name[0] {
+			case 'R':
+				if prevClose <= level.Price && curr > level.Price {
+					res.Breaks = append(res.Breaks, ref)
+				}
+			case 'S':
+				if prevClose >= level.Price && curr < level.Price {
+					res.Breaks = append(res.Breaks, ref)
+				}
+			}
+		}
+	}
+	return res
+}
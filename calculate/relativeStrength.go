@@ -0,0 +1,145 @@
+package calculate
+
+import (
+	"IndicatorTask/binanceFapi"
+	"IndicatorTask/config"
+	"IndicatorTask/utils/logger"
+	"math"
+	"strings"
+)
+
+// RelativeStrength 相对某一基准 (如 BTCUSDT) 的强弱
+type RelativeStrength struct {
+	Benchmark   string
+	Rs          float64 // 相对强弱线 (收盘价 / 基准收盘价)
+	RsChange    float64 // 最近 Window 根K线 RS 线涨跌幅 (%)，即 (1+自身收益)/(1+基准收益)-1
+	Return      float64 // 最近 Window 根K线自身涨跌幅 (%)
+	BenchReturn float64 // 最近 Window 根K线基准涨跌幅 (%)
+	Beta        float64 // 滚动 Beta
+	Correlation float64 // 滚动相关系数
+	RsRsi       float64 // RS 线 RSI
+	RsMacdHist  float64 // RS 线 MACD 柱
+	RsCross     int     // 0: 无, 1: RS 线 MACD 金叉, 2: 死叉 (最新K线)
+	Streak      int     // RS 线连续位于其 EMA 上方的K线数，负数为连续位于下方 (跑输)
+}
+
+// 本轮计算所用基准K线
+func loadRsBenchmarks(cycle string, params config.RelativeStrength) map[string][]binanceFapi.KLine {
+	benchmarks := make(map[string][]binanceFapi.KLine)
+	for _, symbol := range rsBenchmarkSymbols(params) {
+		klines, err := getCycleKlines(symbol, cycle)
+		if err != nil {
+			logger.Log.Error("获取基准K线失败:", map[string]interface{}{"symbol": symbol, "err": err})
+			continue
+		}
+		benchmarks[symbol] = klines
+	}
+	return benchmarks
+}
+
+func rsBenchmarkSymbols(params config.RelativeStrength) []string {
+	if len(params.Benchmarks) == 0 {
+		return []string{"BTCUSDT", "ETHUSDT"}
+	}
+	return params.Benchmarks
+}
+
+// 按开盘时间对齐两组K线的收盘价
+func alignCloses(klines, bench []binanceFapi.KLine) ([]float64, []float64) {
+	benchClose := make(map[int64]float64, len(bench))
+	for _, k := range bench {
+		benchClose[k.OpenTime] = k.Close
+	}
+	var a, b []float64
+	for _, k := range klines {
+		if c, ok := benchClose[k.OpenTime]; ok && c > 0 {
+			a = append(a, k.Close)
+			b = append(b, c)
+		}
+	}
+	return a, b
+}
+
+// 滚动 Beta 与相关系数 (逐根收益率)
+func betaCorrelation(a, b []float64) (float64, float64) {
+	n := len(a) - 1
+	if n < 2 {
+		return 0, 0
+	}
+	ra, rb := make([]float64, n), make([]float64, n)
+	var meanA, meanB float64
+	for i := 0; i < n; i++ {
+		ra[i] = a[i+1]/a[i] - 1
+		rb[i] = b[i+1]/b[i] - 1
+		meanA += ra[i]
+		meanB += rb[i]
+	}
+	meanA /= float64(n)
+	meanB /= float64(n)
+	var cov, varA, varB float64
+	for i := 0; i < n; i++ {
+		cov += (ra[i] - meanA) * (rb[i] - meanB)
+		varA += (ra[i] - meanA) * (ra[i] - meanA)
+		varB += (rb[i] - meanB) * (rb[i] - meanB)
+	}
+	if varA == 0 || varB == 0 {
+		return 0, 0
+	}
+	return cov / varB, cov / math.Sqrt(varA*varB)
+}
+
+// 计算相对各基准的强弱，交易对本身为基准时跳过
+func calculateRelativeStrength(symbol string, klines []binanceFapi.KLine, benchmarks map[string][]binanceFapi.KLine, params config.RelativeStrength) []RelativeStrength {
+	if params.Window <= 0 {
+		params.Window = 30
+	}
+	if params.EmaPeriod <= 0 {
+		params.EmaPeriod = 20
+	}
+	var res []RelativeStrength
+	for _, name := range rsBenchmarkSymbols(params) {
+		bench, ok := benchmarks[name]
+		if !ok || strings.EqualFold(name, symbol) {
+			continue
+		}
+		a, b := alignCloses(klines, bench)
+		n := len(a)
+		if n <= params.Window {
+			continue
+		}
+		line := make([]float64, n)
+		for i := range a {
+			line[i] = a[i] / b[i]
+		}
+
+		rs := RelativeStrength{Benchmark: name, Rs: line[n-1]}
+		from := n - 1 - params.Window
+		rs.Return = (a[n-1]/a[from] - 1) * 100
+		rs.BenchReturn = (b[n-1]/b[from] - 1) * 100
+		rs.RsChange = (line[n-1]/line[from] - 1) * 100
+		rs.Beta, rs.Correlation = betaCorrelation(a[from:], b[from:])
+
+		rsi := calculateRsiSeries(line, config.Cfg.Benchmark.Rsi.Period)
+		rs.RsRsi = rsi[n-1]
+		macd, signalLine, hist := calculateMACD(line)
+		rs.RsMacdHist = hist[n-1]
+		if macd[n-2] <= signalLine[n-2] && macd[n-1] > signalLine[n-1] {
+			rs.RsCross = 1
+		} else if macd[n-2] >= signalLine[n-2] && macd[n-1] < signalLine[n-1] {
+			rs.RsCross = 2
+		}
+
+		ema := calculateEMA(line, params.EmaPeriod)
+		for i := n - 1; i >= 0; i-- {
+			if line[i] > ema[i] && rs.Streak >= 0 {
+				rs.Streak++
+			} else if line[i] < ema[i] && rs.Streak <= 0 {
+				rs.Streak--
+			} else {
+				break
+			}
+		}
+		res = append(res, rs)
+	}
+	return res
+}
@@ -57,3 +57,46 @@ func GetRsi(prices []float64) float64 {
 	}
 	return rsi
 }
+
+// 计算RSI序列 (Wilder 平滑)，数据不足的位置为 50
+func calculateRsiSeries(prices []float64, period int) []float64 {
+	rsi := make([]float64, len(prices))
+	for i := range rsi {
+		rsi[i] = 50
+	}
+	if period <= 0 || len(prices) <= period {
+		return rsi
+	}
+
+	var avgGain, avgLoss float64
+	for i := 1; i < len(prices); i++ {
+		diff := prices[i] - prices[i-1]
+		gain, loss := 0.0, 0.0
+		if diff >= 0 {
+			gain = diff
+		} else {
+			loss = -diff
+		}
+
+		if i <= period {
+			avgGain += gain / float64(period)
+			avgLoss += loss / float64(period)
+			if i < period {
+				continue
+			}
+		} else {
+			avgGain = (avgGain*float64(period-1) + gain) / float64(period)
+			avgLoss = (avgLoss*float64(period-1) + loss) / float64(period)
+		}
+
+		switch {
+		case avgGain == 0 && avgLoss == 0:
+			rsi[i] = 50
+		case avgLoss == 0:
+			rsi[i] = 100
+		default:
+			rsi[i] = 100 - 100/(1+avgGain/avgLoss)
+		}
+	}
+	return rsi
+}
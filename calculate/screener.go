@@ -0,0 +1,149 @@
+package calculate
+
+import (
+	"IndicatorTask/binanceFapi"
+	"IndicatorTask/config"
+	"IndicatorTask/store"
+	"IndicatorTask/utils/logger"
+	"IndicatorTask/utils/notify"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cryptoSelect/public/database"
+	"gorm.io/gorm"
+)
+
+// 单个交易对在本轮的截面数据 (以最后一根已收盘K线为准)
+type screenerRow struct {
+	Symbol        string
+	Change        float64 // 涨跌幅 (%)
+	VolumeSpike   float64 // 成交量 / 前 20 根均量
+	Rsi           float64
+	Rate          float64 // 资金费率
+	TakerBuyRatio float64 // 主动买入占比 (%)
+	RsChange      float64 // 相对第一个基准的 RS 线涨跌幅 (%)
+	HasRs         bool
+}
+
+// 排行榜类别
+type screenerCategory struct {
+	Name   string
+	Title  string
+	Value  func(screenerRow) float64
+	Desc   bool // true: 从大到小
+	Valid  func(screenerRow) bool
+	Format string // 数值格式
+}
+
+var screenerCategories = []screenerCategory{
+	{Name: "gainers", Title: "涨幅榜", Value: func(r screenerRow) float64 { return r.Change }, Desc: true, Format: "%.2f%%"},
+	{Name: "losers", Title: "跌幅榜", Value: func(r screenerRow) float64 { return r.Change }, Format: "%.2f%%"},
+	{Name: "volume_spike", Title: "放量榜", Value: func(r screenerRow) float64 { return r.VolumeSpike }, Desc: true, Format: "%.2fx"},
+	{Name: "rsi_oversold", Title: "RSI超卖", Value: func(r screenerRow) float64 { return r.Rsi }, Format: "%.1f"},
+	{Name: "rsi_overbought", Title: "RSI超买", Value: func(r screenerRow) float64 { return r.Rsi }, Desc: true, Format: "%.1f"},
+	{Name: "funding", Title: "资金费率", Value: func(r screenerRow) float64 { return r.Rate }, Desc: true, Format: "%.4f%%"},
+	{Name: "taker_buy", Title: "主动买入", Value: func(r screenerRow) float64 { return r.TakerBuyRatio }, Desc: true, Format: "%.2f%%"},
+	{Name: "relative_strength", Title: "相对强弱", Value: func(r screenerRow) float64 { return r.RsChange }, Desc: true, Format: "%.2f%%",
+		Valid: func(r screenerRow) bool { return r.HasRs }},
+}
+
+// 生成截面数据，使用最后一根已收盘K线，避免周期刚开始时K线数据过少
+func newScreenerRow(info *binanceFapi.SymbolInfo, klines []binanceFapi.KLine, ind *indicatorResult) screenerRow {
+	row := screenerRow{Symbol: info.Symbol, Rsi: info.Rsi, Rate: info.Rate}
+	closed := closedKlines(klines)
+	n := len(closed)
+	if n == 0 {
+		return row
+	}
+	last := closed[n-1]
+	if last.Open > 0 {
+		row.Change = (last.Close - last.Open) / last.Open * 100
+	}
+	if last.Volume > 0 {
+		row.TakerBuyRatio = last.TakerBuyVolume / last.Volume * 100
+	}
+	var avgVol float64
+	count := 0
+	for i := n - 21; i < n-1; i++ {
+		if i >= 0 {
+			avgVol += closed[i].Volume
+			count++
+		}
+	}
+	if count > 0 && avgVol > 0 {
+		row.VolumeSpike = last.Volume / (avgVol / float64(count))
+	}
+	if len(ind.Relative) > 0 {
+		row.RsChange, row.HasRs = ind.Relative[0].RsChange, true
+	}
+	return row
+}
+
+// 截面排名：入库快照并按需推送汇总消息
+func runScreener(cycle string, rows []screenerRow, params config.Screener) {
+	if params.TopN <= 0 {
+		params.TopN = 5
+	}
+	if len(rows) == 0 {
+		return
+	}
+	now := time.Now()
+	var records []store.ScreenerRecord
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("     ---- 【  市场排行 %s  】 ---- \n", cycle))
+
+	for _, c := range screenerCategories {
+		var list []screenerRow
+		for _, r := range rows {
+			if c.Valid == nil || c.Valid(r) {
+				list = append(list, r)
+			}
+		}
+		if len(list) == 0 {
+			continue
+		}
+		sort.SliceStable(list, func(i, j int) bool {
+			if c.Desc {
+				return c.Value(list[i]) > c.Value(list[j])
+			}
+			return c.Value(list[i]) < c.Value(list[j])
+		})
+		if len(list) > params.TopN {
+			list = list[:params.TopN]
+		}
+
+		items := make([]string, 0, len(list))
+		for i, r := range list {
+			records = append(records, store.ScreenerRecord{
+				Cycle:      cycle,
+				Category:   c.Name,
+				Rank:       i + 1,
+				Symbol:     r.Symbol,
+				Value:      c.Value(r),
+				SnapshotAt: now,
+			})
+			items = append(items, strings.TrimSuffix(r.Symbol, "USDT")+" "+fmt.Sprintf(c.Format, c.Value(r)))
+		}
+		builder.WriteString(fmt.Sprintf("%s: %s\n", c.Title, strings.Join(items, ", ")))
+	}
+
+	// 每个周期只保留最新一次快照，清理与写入在同一事务中，失败时保留上一次快照
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("cycle = ?", cycle).Delete(&store.ScreenerRecord{}).Error; err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			return nil
+		}
+		return tx.Create(&records).Error
+	})
+	if err != nil {
+		logger.Log.Error("保存市场排行失败", map[string]interface{}{"cycle": cycle, "err": err})
+	}
+
+	if params.Push && config.Cfg.Notify.IsEnable {
+		notify.SendTelegramMessage(cycle, builder.String())
+	}
+}
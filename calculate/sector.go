@@ -0,0 +1,179 @@
+package calculate
+
+import (
+	"IndicatorTask/binanceFapi"
+	"IndicatorTask/config"
+	"IndicatorTask/store"
+	"IndicatorTask/utils/logger"
+	"IndicatorTask/utils/notify"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cryptoSelect/public/database"
+)
+
+// 单个交易对在本轮的板块统计数据 (以最后一根已收盘K线为准)
+type sectorRow struct {
+	Symbol      string
+	BarTime     int64
+	Change      float64 // 最后一根已收盘K线涨跌幅 (%)
+	Return      float64 // 最近 Window 根K线涨跌幅 (%)
+	QuoteVolume float64
+	AboveEma20  bool
+}
+
+func newSectorRow(symbol string, klines []binanceFapi.KLine, params config.Sector) (sectorRow, bool) {
+	if params.Window <= 0 {
+		params.Window = 20
+	}
+	closed := closedKlines(klines)
+	n := len(closed)
+	if n < params.Window+1 || n < 20 {
+		return sectorRow{}, false
+	}
+	last := closed[n-1]
+	closes := binanceFapi.ClosePrice(closed)
+	ema := calculateEMA(closes, 20)
+	row := sectorRow{
+		Symbol:      symbol,
+		BarTime:     last.OpenTime,
+		QuoteVolume: last.QuoteVolume,
+		AboveEma20:  last.Close > ema[n-1],
+	}
+	if last.Open > 0 {
+		row.Change = (last.Close - last.Open) / last.Open * 100
+	}
+	if from := closed[n-1-params.Window].Close; from > 0 {
+		row.Return = (last.Close/from - 1) * 100
+	}
+	return row, true
+}
+
+// 中位数
+func median(values []float64) float64 {
+	n := len(values)
+	if n == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// 计算各板块汇总数据并入库，相对强弱由负转正 / 由正转负时发出板块轮动提醒
+func runSectors(cycle string, rows []sectorRow) {
+	if len(rows) == 0 || len(config.Sectors) == 0 {
+		return
+	}
+	var totalVolume float64
+	var barTime int64
+	marketReturns := make([]float64, 0, len(rows))
+	members := make(map[string][]sectorRow)
+	for _, r := range rows {
+		totalVolume += r.QuoteVolume
+		marketReturns = append(marketReturns, r.Return)
+		if r.BarTime > barTime {
+			barTime = r.BarTime
+		}
+		for _, sector := range config.SymbolSectors(r.Symbol) {
+			members[sector] = append(members[sector], r)
+		}
+	}
+	marketReturn := median(marketReturns)
+
+	names := make([]string, 0, len(members))
+	for name := range members {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var rotations []string
+	for _, name := range names {
+		list := members[name]
+		rec := store.SectorRecord{Sector: name, Cycle: cycle, BarTime: time.UnixMilli(barTime), Members: len(list)}
+		changes := make([]float64, 0, len(list))
+		returns := make([]float64, 0, len(list))
+		var volume float64
+		above, advances := 0, 0
+		for _, r := range list {
+			changes = append(changes, r.Change)
+			returns = append(returns, r.Return)
+			volume += r.QuoteVolume
+			if r.AboveEma20 {
+				above++
+			}
+			if r.Change > 0 {
+				advances++
+			}
+		}
+		rec.MedianChange = median(changes)
+		rec.MedianReturn = median(returns)
+		rec.RelativeStrength = rec.MedianReturn - marketReturn
+		rec.Advancing = percent(advances, len(list))
+		rec.AboveEma20 = percent(above, len(list))
+		if totalVolume > 0 {
+			rec.VolumeShare = volume / totalVolume * 100
+		}
+
+		// 同一根K线已统计过则跳过
+		var count int64
+		err := database.DB.Model(&store.SectorRecord{}).Where("sector = ? AND cycle = ? AND bar_time = ?", name, cycle, rec.BarTime).Count(&count).Error
+		if err != nil {
+			logger.Log.Error("查询板块记录失败", map[string]interface{}{"sector": name, "cycle": cycle, "err": err})
+			continue
+		}
+		if count > 0 {
+			continue
+		}
+		var prev store.SectorRecord
+		hasPrev := database.DB.Where("sector = ? AND cycle = ?", name, cycle).Order("bar_time DESC").Limit(1).Find(&prev).RowsAffected > 0
+		if hasPrev {
+			if prev.RelativeStrength <= 0 && rec.RelativeStrength > 0 {
+				rec.Rotation = 1
+			} else if prev.RelativeStrength >= 0 && rec.RelativeStrength < 0 {
+				rec.Rotation = 2
+			}
+		}
+		if err := database.DB.Create(&rec).Error; err != nil {
+			logger.Log.Error("保存板块数据失败", map[string]interface{}{"sector": name, "cycle": cycle, "err": err})
+			continue
+		}
+
+		if rec.Rotation != 0 {
+			msg := sectorMsgFmt(rec)
+			rotations = append(rotations, msg)
+			// 订阅了该板块的用户
+			notify.PushKind(sectorSubscription(name), cycle, notify.KindSector, msg)
+		}
+	}
+
+	if len(rotations) > 0 && config.Cfg.Notify.IsEnable {
+		notify.SendTelegramMessage(cycle, strings.Join(rotations, "\n"))
+	}
+}
+
+// 板块订阅使用的 symbol，如 SECTOR:DeFi
+func sectorSubscription(sector string) string {
+	return notify.SectorPrefix + sector
+}
+
+// 板块轮动提醒
+func sectorMsgFmt(rec store.SectorRecord) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("     ---- 【  板块 %s %s  】 ---- \n", rec.Sector, rec.Cycle))
+	if rec.Rotation == 1 {
+		builder.WriteString("轮动: 相对强弱转强\n")
+	} else {
+		builder.WriteString("轮动: 相对强弱转弱\n")
+	}
+	builder.WriteString(fmt.Sprintf("相对强弱: %+.2f%% (板块 %.2f%%)\n", rec.RelativeStrength, rec.MedianReturn))
+	builder.WriteString(fmt.Sprintf("涨跌幅中位数: %.2f%%\n", rec.MedianChange))
+	builder.WriteString(fmt.Sprintf("上涨占比: %.1f%% EMA20上方: %.1f%%\n", rec.Advancing, rec.AboveEma20))
+	builder.WriteString(fmt.Sprintf("成交额占比: %.2f%% (%d 个币种)\n", rec.VolumeShare, rec.Members))
+	return builder.String()
+}
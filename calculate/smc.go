@@ -2,127 +2,197 @@ package calculate
 
 import (
 	"IndicatorTask/binanceFapi"
+	"IndicatorTask/config"
 	"fmt"
 	"math"
 )
 
+// PriceZone 价格区间 (FVG / OB)
+type PriceZone struct {
+	Type int // 0: 无, 1: 看涨, 2: 看跌
+	Low  float64
+	High float64
+}
+
+// 区间描述，如 Bullish: [1.00 - 1.20]
+func (z PriceZone) String() string {
+	switch z.Type {
+	case 1:
+		return fmt.Sprintf("Bullish: [%.4f - %.4f]", z.Low, z.High)
+	case 2:
+		return fmt.Sprintf("Bearish: [%.4f - %.4f]", z.Low, z.High)
+	}
+	return ""
+}
+
 // SMCResult represents the technical levels identified by the SMC algorithm
 type SMCResult struct {
 	Support    float64
 	Resistance float64
-	Signal     string // BOS, CHoCH, or empty
-	Fvg        string // FVG range
-	Ob         string // OB range
+	Trend      int    // 市场结构 0: 未知, 1: 看涨, 2: 看跌
+	Signal     string // BOS-Bullish / BOS-Bearish / CHoCH-Bullish / CHoCH-Bearish, or empty
+	Fvg        PriceZone
+	Ob         PriceZone
+	EqualHighs float64 // 等高点价位 (买方流动性)，0 表示无
+	EqualLows  float64 // 等低点价位 (卖方流动性)，0 表示无
+	Sweep      int     // 0: 无, 1: 扫下方流动性后收回 (看涨), 2: 扫上方流动性后收回 (看跌)
+	RangeHigh  float64 // 交易区间 (最近确认的波段高点)
+	RangeLow   float64 // 交易区间 (最近确认的波段低点)
+	Zone       int     // 0: 无, 1: 溢价区, 2: 折价区, 3: 平衡区
 }
 
-// detectSMC 分析高低点、支撑压力位以及结构突破 (BOS/CHoCH)，并增加 FVG 和 OB 分析
-func detectSMC(klines []binanceFapi.KLine) SMCResult {
+// detectSMC 分析高低点、支撑压力位以及结构突破 (BOS/CHoCH)，并增加 FVG、OB、流动性与溢价折价区分析
+func detectSMC(klines []binanceFapi.KLine, params config.Smc) SMCResult {
 	n := len(klines)
 	if n < 30 {
 		return SMCResult{}
 	}
-
-	// 1. 设置分析范围：最多最近 50 根 K 线
-	lookback := 50
-	if n < lookback {
-		lookback = n
+	if params.Window <= 0 {
+		params.Window = 5
+	}
+	if params.Lookback <= 0 {
+		params.Lookback = 50
 	}
-	startIdx := n - lookback
-	if startIdx < 0 {
-		startIdx = 0
+	if params.EqualTolerance <= 0 {
+		params.EqualTolerance = 0.1
 	}
+	window := params.Window
 
-	// 识别 Pivot High 和 Pivot Low (窗口大小为 5，表示 5+1+5 的结构)
-	var pivotHighs []float64
-	var pivotLows []float64
+	// 1. 识别全部 Pivot High / Pivot Low (窗口大小为 window，表示 window+1+window 的结构)
+	highs, lows := findSwings(klines, window, 0)
 
-	window := 5
-	// 我们从 startIdx 开始寻找在该 50 根范围内形成的 Pivot
-	for i := startIdx; i < n-window; i++ {
-		// 保证左侧有足够数据
-		if i < window {
-			continue
-		}
+	res := SMCResult{}
 
-		isHigh := true
-		isLow := true
-		for j := 1; j <= window; j++ {
-			if klines[i].High < klines[i-j].High || klines[i].High < klines[i+j].High {
-				isHigh = false
-			}
-			if klines[i].Low > klines[i-j].Low || klines[i].Low > klines[i+j].Low {
-				isLow = false
-			}
+	// 2. 提取最近 Lookback 根内的最高压力和最低支撑 (即 SMC 的强区域)
+	startIdx := n - params.Lookback
+	for _, h := range highs {
+		if h.Index >= startIdx && h.Price > res.Resistance {
+			res.Resistance = h.Price
 		}
-		if isHigh {
-			pivotHighs = append(pivotHighs, klines[i].High)
-		}
-		if isLow {
-			pivotLows = append(pivotLows, klines[i].Low)
+	}
+	for _, l := range lows {
+		if l.Index >= startIdx && (res.Support == 0 || l.Price < res.Support) {
+			res.Support = l.Price
 		}
 	}
 
-	res := SMCResult{}
+	// 3. 按时间推进跟踪市场结构：收盘突破最近未被突破的波段点
+	//    与当前结构同向为 BOS，反向为 CHoCH；波段点在其右侧 window 根K线走完后才确认
+	var lastHigh, lastLow *swingPoint
+	hi, li := 0, 0
+	for i := 0; i < n; i++ {
+		for hi < len(highs) && highs[hi].Index+window < i {
+			lastHigh = &highs[hi]
+			hi++
+		}
+		for li < len(lows) && lows[li].Index+window < i {
+			lastLow = &lows[li]
+			li++
+		}
 
-	// 2. 提取最近 50 根内的最高压力和最低支撑 (即 SMC 的强区域)
-	if len(pivotHighs) > 0 {
-		maxHigh := pivotHighs[0]
-		for _, v := range pivotHighs {
-			if v > maxHigh {
-				maxHigh = v
+		signal := ""
+		if lastHigh != nil && klines[i].Close > lastHigh.Price {
+			if res.Trend == 2 {
+				signal = "CHoCH-Bullish"
+			} else {
+				signal = "BOS-Bullish"
 			}
-		}
-		res.Resistance = maxHigh
-	}
-	if len(pivotLows) > 0 {
-		minLow := pivotLows[0]
-		for _, v := range pivotLows {
-			if v < minLow {
-				minLow = v
+			res.Trend = 1
+			lastHigh = nil
+		} else if lastLow != nil && klines[i].Close < lastLow.Price {
+			if res.Trend == 1 {
+				signal = "CHoCH-Bearish"
+			} else {
+				signal = "BOS-Bearish"
 			}
+			res.Trend = 2
+			lastLow = nil
+		}
+		if i == n-1 {
+			res.Signal = signal
 		}
-		res.Support = minLow
 	}
 
-	// 3. FVG (Fair Value Gap) 检测
+	// 4. FVG (Fair Value Gap) 检测
 	// 扫描最近 20 根 K 线寻找最新的缺口
 	for i := n - 1; i >= n-20 && i >= 2; i-- {
 		// Bullish FVG: Low[i] > High[i-2]
 		if klines[i].Low > klines[i-2].High && klines[i-1].Close > klines[i-1].Open {
-			res.Fvg = "Bullish: [" + fmt.Sprintf("%.2f", klines[i-2].High) + " - " + fmt.Sprintf("%.2f", klines[i].Low) + "]"
+			res.Fvg = PriceZone{Type: 1, Low: klines[i-2].High, High: klines[i].Low}
 			break
 		}
 		// Bearish FVG: High[i] < Low[i-2]
 		if klines[i].High < klines[i-2].Low && klines[i-1].Close < klines[i-1].Open {
-			res.Fvg = "Bearish: [" + fmt.Sprintf("%.2f", klines[i].High) + " - " + fmt.Sprintf("%.2f", klines[i-2].Low) + "]"
+			res.Fvg = PriceZone{Type: 2, Low: klines[i].High, High: klines[i-2].Low}
 			break
 		}
 	}
 
-	// 4. 结构突破判定 (BOS/CHoCH) 与 OB (Order Block)
-	currClose := klines[n-1].Close
-	prevClose := klines[n-2].Close
-
-	if res.Resistance > 0 && currClose > res.Resistance && prevClose <= res.Resistance {
-		res.Signal = "BOS-Bullish"
-		// 寻找 OB: 在突破波段开始前的最后一根阴线
+	// 5. OB (Order Block)：结构突破时，突破波段开始前的最后一根反向K线
+	if res.Signal == "BOS-Bullish" || res.Signal == "CHoCH-Bullish" {
 		for i := n - 2; i >= n-20 && i >= 0; i-- {
 			if klines[i].Close < klines[i].Open {
-				res.Ob = "Bullish OB: [" + fmt.Sprintf("%.2f", klines[i].Low) + " - " + fmt.Sprintf("%.2f", klines[i].High) + "]"
+				res.Ob = PriceZone{Type: 1, Low: klines[i].Low, High: klines[i].High}
 				break
 			}
 		}
-	} else if res.Support > 0 && currClose < res.Support && prevClose >= res.Support {
-		res.Signal = "BOS-Bearish"
-		// 寻找 OB: 在跌破波段开始前的最后一根阳线
+	} else if res.Signal == "BOS-Bearish" || res.Signal == "CHoCH-Bearish" {
 		for i := n - 2; i >= n-20 && i >= 0; i-- {
 			if klines[i].Close > klines[i].Open {
-				res.Ob = "Bearish OB: [" + fmt.Sprintf("%.2f", klines[i].Low) + " - " + fmt.Sprintf("%.2f", klines[i].High) + "]"
+				res.Ob = PriceZone{Type: 2, Low: klines[i].Low, High: klines[i].High}
 				break
 			}
 		}
 	}
 
+	// 6. 等高等低 (流动性池) 与流动性扫荡
+	tolerance := params.EqualTolerance / 100
+	if len(highs) >= 2 {
+		a, b := highs[len(highs)-2], highs[len(highs)-1]
+		if math.Abs(a.Price-b.Price) <= tolerance*b.Price {
+			res.EqualHighs = getMax(a.Price, b.Price)
+		}
+	}
+	if len(lows) >= 2 {
+		a, b := lows[len(lows)-2], lows[len(lows)-1]
+		if math.Abs(a.Price-b.Price) <= tolerance*b.Price {
+			res.EqualLows = getMin(a.Price, b.Price)
+		}
+	}
+
+	curr := klines[n-1]
+	buySide := res.EqualHighs
+	if buySide == 0 && len(highs) > 0 {
+		buySide = highs[len(highs)-1].Price
+	}
+	sellSide := res.EqualLows
+	if sellSide == 0 && len(lows) > 0 {
+		sellSide = lows[len(lows)-1].Price
+	}
+	if buySide > 0 && curr.High > buySide && curr.Close < buySide {
+		res.Sweep = 2
+	} else if sellSide > 0 && curr.Low < sellSide && curr.Close > sellSide {
+		res.Sweep = 1
+	}
+
+	// 7. 溢价/折价区：以最近确认的波段高低点为交易区间，中线上下 5% 为平衡区
+	if len(highs) > 0 && len(lows) > 0 {
+		res.RangeHigh = highs[len(highs)-1].Price
+		res.RangeLow = lows[len(lows)-1].Price
+		if res.RangeHigh > res.RangeLow {
+			mid := (res.RangeHigh + res.RangeLow) / 2
+			band := (res.RangeHigh - res.RangeLow) * 0.05
+			switch {
+			case curr.Close > mid+band:
+				res.Zone = 1
+			case curr.Close < mid-band:
+				res.Zone = 2
+			default:
+				res.Zone = 3
+			}
+		}
+	}
+
 	return res
 }
 
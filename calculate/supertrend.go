@@ -0,0 +1,167 @@
+package calculate
+
+import "IndicatorTask/binanceFapi"
+
+// TrendStopResult 趋势跟踪指标的最新状态
+type TrendStopResult struct {
+	Trend int     // 0: 无, 1: 多头, 2: 空头
+	Stop  float64 // 当前跟踪止损位 (超级趋势线 / SAR 点)
+	Flip  int     // 0: 无, 1: 最新K线翻多, 2: 最新K线翻空
+}
+
+// 计算 ATR (Wilder 平滑)
+func calculateATR(klines []binanceFapi.KLine, period int) []float64 {
+	n := len(klines)
+	atr := make([]float64, n)
+	if n == 0 || period <= 0 {
+		return atr
+	}
+
+	var sum float64
+	for i := 0; i < n; i++ {
+		tr := klines[i].High - klines[i].Low
+		if i > 0 {
+			prevClose := klines[i-1].Close
+			tr = max(tr, max(abs(klines[i].High-prevClose), abs(klines[i].Low-prevClose)))
+		}
+		if i < period {
+			sum += tr
+			atr[i] = sum / float64(i+1)
+		} else {
+			atr[i] = (atr[i-1]*float64(period-1) + tr) / float64(period)
+		}
+	}
+	return atr
+}
+
+func abs(f float64) float64 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
+
+// 计算超级趋势
+func calculateSupertrend(klines []binanceFapi.KLine, period int, multiplier float64) TrendStopResult {
+	n := len(klines)
+	if period <= 0 {
+		period = 10
+	}
+	if multiplier <= 0 {
+		multiplier = 3
+	}
+	if n < period+2 {
+		return TrendStopResult{}
+	}
+
+	atr := calculateATR(klines, period)
+	var upper, lower float64
+	trend := 1
+	prevTrend := 1
+	for i := period; i < n; i++ {
+		hl2 := (klines[i].High + klines[i].Low) / 2
+		basicUpper := hl2 + multiplier*atr[i]
+		basicLower := hl2 - multiplier*atr[i]
+
+		if i == period {
+			upper, lower = basicUpper, basicLower
+			if klines[i].Close < hl2 {
+				trend = 2
+			}
+			prevTrend = trend
+			continue
+		}
+
+		prevClose := klines[i-1].Close
+		// 上轨只降不升，下轨只升不降，除非前收盘已突破
+		if basicUpper < upper || prevClose > upper {
+			upper = basicUpper
+		}
+		if basicLower > lower || prevClose < lower {
+			lower = basicLower
+		}
+
+		prevTrend = trend
+		if trend == 2 && klines[i].Close > upper {
+			trend = 1
+		} else if trend == 1 && klines[i].Close < lower {
+			trend = 2
+		}
+	}
+
+	res := TrendStopResult{Trend: trend, Stop: lower}
+	if trend == 2 {
+		res.Stop = upper
+	}
+	if trend != prevTrend {
+		res.Flip = trend
+	}
+	return res
+}
+
+// 计算抛物线 SAR
+func calculateSAR(klines []binanceFapi.KLine, step, maxStep float64) TrendStopResult {
+	n := len(klines)
+	if step <= 0 {
+		step = 0.02
+	}
+	if maxStep <= 0 {
+		maxStep = 0.2
+	}
+	if n < 3 {
+		return TrendStopResult{}
+	}
+
+	// 初始方向由前两根K线决定
+	trend := 1
+	sar := klines[0].Low
+	ep := klines[0].High
+	if klines[1].Close < klines[0].Close {
+		trend = 2
+		sar = klines[0].High
+		ep = klines[0].Low
+	}
+	af := step
+	prevTrend := trend
+
+	for i := 1; i < n; i++ {
+		prevTrend = trend
+		sar = sar + af*(ep-sar)
+
+		if trend == 1 {
+			// SAR 不得高于前两根K线的最低价
+			sar = min(sar, klines[i-1].Low)
+			if i > 1 {
+				sar = min(sar, klines[i-2].Low)
+			}
+			if klines[i].Low < sar {
+				trend, sar, ep, af = 2, ep, klines[i].Low, step
+				continue
+			}
+			if klines[i].High > ep {
+				ep = klines[i].High
+				af = min(af+step, maxStep)
+			}
+		} else {
+			// SAR 不得低于前两根K线的最高价
+			sar = max(sar, klines[i-1].High)
+			if i > 1 {
+				sar = max(sar, klines[i-2].High)
+			}
+			if klines[i].High > sar {
+				trend, sar, ep, af = 1, ep, klines[i].High, step
+				continue
+			}
+			if klines[i].Low < ep {
+				ep = klines[i].Low
+				af = min(af+step, maxStep)
+			}
+		}
+	}
+
+	res := TrendStopResult{Trend: trend, Stop: sar}
+	if trend != prevTrend {
+		res.Flip = trend
+	}
+	return res
+}
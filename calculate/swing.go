@@ -0,0 +1,37 @@
+package calculate
+
+import "IndicatorTask/binanceFapi"
+
+// 波段高低点
+type swingPoint struct {
+	Index int     // 原始K线下标
+	Price float64 // 高点取 High，低点取 Low
+}
+
+// 识别 start 之后的波段高低点 (左右各 window 根K线的结构，与 detectSMC 的 Pivot 规则一致)
+func findSwings(klines []binanceFapi.KLine, window, start int) ([]swingPoint, []swingPoint) {
+	var highs, lows []swingPoint
+	n := len(klines)
+	if start < window {
+		start = window
+	}
+	for i := start; i < n-window; i++ {
+		isHigh := true
+		isLow := true
+		for j := 1; j <= window; j++ {
+			if klines[i].High < klines[i-j].High || klines[i].High < klines[i+j].High {
+				isHigh = false
+			}
+			if klines[i].Low > klines[i-j].Low || klines[i].Low > klines[i+j].Low {
+				isLow = false
+			}
+		}
+		if isHigh {
+			highs = append(highs, swingPoint{Index: i, Price: klines[i].High})
+		}
+		if isLow {
+			lows = append(lows, swingPoint{Index: i, Price: klines[i].Low})
+		}
+	}
+	return highs, lows
+}
@@ -0,0 +1,182 @@
+package calculate
+
+import (
+	"IndicatorTask/binanceFapi"
+	"IndicatorTask/config"
+)
+
+// K线表示方式
+const (
+	TransformHeikinAshi = "heikinashi"
+	TransformRenko      = "renko"
+	TransformRange      = "range"
+)
+
+// 平均K线 (Heikin-Ashi)
+func heikinAshi(klines []binanceFapi.KLine) []binanceFapi.KLine {
+	res := make([]binanceFapi.KLine, len(klines))
+	for i, k := range klines {
+		ha := k
+		ha.Close = (k.Open + k.High + k.Low + k.Close) / 4
+		if i == 0 {
+			ha.Open = (k.Open + k.Close) / 2
+		} else {
+			ha.Open = (res[i-1].Open + res[i-1].Close) / 2
+		}
+		ha.High = max(k.High, max(ha.Open, ha.Close))
+		ha.Low = min(k.Low, min(ha.Open, ha.Close))
+		res[i] = ha
+	}
+	return res
+}
+
+// 按收盘价生成砖块：收盘高于最后砖块上沿一个砖块以上时向上，低于下沿一个砖块以上时向下
+// 同一根K线形成的多块砖均分其时间区间与累计成交量，保证砖块时间唯一递增且成交量不为 0
+func renkoBars(klines []binanceFapi.KLine, box float64) []binanceFapi.KLine {
+	if len(klines) == 0 || box <= 0 {
+		return nil
+	}
+	var res []binanceFapi.KLine
+	top, bottom := klines[0].Close, klines[0].Close
+	var volume, quoteVolume, takerVolume float64
+	for _, k := range klines {
+		volume += k.Volume
+		quoteVolume += k.QuoteVolume
+		takerVolume += k.TakerBuyVolume
+
+		var bricks []binanceFapi.KLine
+		for k.Close >= top+box || k.Close <= bottom-box {
+			var brick binanceFapi.KLine
+			if k.Close >= top+box {
+				brick.Open, brick.Close = top, top+box
+			} else {
+				brick.Open, brick.Close = bottom, bottom-box
+			}
+			brick.High, brick.Low = max(brick.Open, brick.Close), min(brick.Open, brick.Close)
+			top, bottom = brick.High, brick.Low
+			bricks = append(bricks, brick)
+		}
+		if len(bricks) == 0 {
+			continue
+		}
+
+		m := int64(len(bricks))
+		duration := k.CloseTime - k.OpenTime + 1
+		for j := range bricks {
+			brick := &bricks[j]
+			brick.OpenTime = k.OpenTime + duration*int64(j)/m
+			brick.CloseTime = k.OpenTime + duration*int64(j+1)/m - 1
+			brick.Volume = volume / float64(m)
+			brick.QuoteVolume = quoteVolume / float64(m)
+			brick.TakerBuyVolume = takerVolume / float64(m)
+		}
+		res = append(res, bricks...)
+		volume, quoteVolume, takerVolume = 0, 0, 0
+	}
+	return res
+}
+
+// 等幅K线：每根K线高低差达到 size 时收线；K线内价格路径按 阳线 O-L-H-C / 阴线 O-H-L-C 近似
+// 原始K线的时间与成交量按路径长度分配到途经的等幅K线，保证时间唯一递增且成交量不为 0
+func rangeBars(klines []binanceFapi.KLine, size float64) []binanceFapi.KLine {
+	if len(klines) == 0 || size <= 0 {
+		return nil
+	}
+	var res []binanceFapi.KLine
+	bar := binanceFapi.KLine{OpenTime: klines[0].OpenTime, Open: klines[0].Open, High: klines[0].Open, Low: klines[0].Open}
+	price := klines[0].Open
+	for _, k := range klines {
+		path := []float64{k.Open, k.High, k.Low, k.Close}
+		if k.Close >= k.Open {
+			path = []float64{k.Open, k.Low, k.High, k.Close}
+		}
+		var length float64
+		last := price
+		for _, target := range path {
+			length += abs(target - last)
+			last = target
+		}
+		if length == 0 {
+			bar.Volume += k.Volume
+			bar.QuoteVolume += k.QuoteVolume
+			bar.TakerBuyVolume += k.TakerBuyVolume
+			continue
+		}
+
+		duration := k.CloseTime - k.OpenTime + 1
+		var travelled float64
+		for _, target := range path {
+			for price != target {
+				// 沿路径移动，超出当前K线允许范围时在边界收线
+				limitHigh, limitLow := bar.Low+size, bar.High-size
+				next, full := target, false
+				if next >= limitHigh {
+					next, full = limitHigh, true
+				} else if next <= limitLow {
+					next, full = limitLow, true
+				}
+				share := abs(next-price) / length
+				bar.Volume += k.Volume * share
+				bar.QuoteVolume += k.QuoteVolume * share
+				bar.TakerBuyVolume += k.TakerBuyVolume * share
+				travelled += abs(next - price)
+
+				price = next
+				bar.High, bar.Low = max(bar.High, price), min(bar.Low, price)
+				// 触及边界即收线，避免浮点误差导致区间永远差一点达不到 size
+				if full {
+					at := k.OpenTime + int64(float64(duration)*min(travelled/length, 1))
+					if at <= bar.OpenTime {
+						at = bar.OpenTime + 1
+					}
+					bar.Close, bar.CloseTime = price, at-1
+					res = append(res, bar)
+					bar = binanceFapi.KLine{OpenTime: at, Open: price, High: price, Low: price}
+				}
+			}
+		}
+	}
+	// 未走完的K线作为最新一根
+	bar.Close, bar.CloseTime = price, klines[len(klines)-1].CloseTime
+	if bar.CloseTime < bar.OpenTime {
+		bar.CloseTime = bar.OpenTime
+	}
+	return append(res, bar)
+}
+
+// 按周期配置转换指标计算所用的K线，转换后数量不足以计算 MACD 时退回原始K线
+func transformKlines(klines []binanceFapi.KLine, params config.Transform) ([]binanceFapi.KLine, bool) {
+	if params.AtrPeriod <= 0 {
+		params.AtrPeriod = 14
+	}
+	n := len(klines)
+	if n == 0 {
+		return klines, false
+	}
+	last := klines[n-1].Close
+
+	var res []binanceFapi.KLine
+	switch params.Type {
+	case TransformHeikinAshi:
+		return heikinAshi(klines), true
+	case TransformRenko:
+		box := last * params.BoxSize / 100
+		if params.BoxSize <= 0 {
+			box = calculateATR(klines, params.AtrPeriod)[n-1]
+		}
+		res = renkoBars(klines, box)
+	case TransformRange:
+		size := last * params.RangeSize / 100
+		if params.RangeSize <= 0 {
+			size = calculateATR(klines, params.AtrPeriod)[n-1]
+		}
+		res = rangeBars(klines, size)
+	default:
+		return klines, false
+	}
+	macd := config.Cfg.Benchmark.Macd
+	if len(res) < macd.SlowPeriod+macd.Window {
+		return klines, false
+	}
+	return res, true
+}
@@ -0,0 +1,158 @@
+package calculate
+
+import (
+	"IndicatorTask/binanceFapi"
+	"IndicatorTask/config"
+)
+
+// VolumeFlowResult 量能指标最新值
+type VolumeFlowResult struct {
+	Obv           float64
+	Mfi           float64
+	Cmf           float64
+	Ad            float64
+	MfiZone       int  // 0: 正常, 1: 超买, 2: 超卖
+	ObvDivergence int  // 0: 无, 1: 底背离 (价新低 OBV 未新低), 2: 顶背离 (价新高 OBV 未新高)
+	Enabled       bool // 已启用且K线数足够计算 (MFI 为 0 是超卖极值，不能以数值判断是否输出)
+}
+
+// 计算 OBV 序列
+func calculateOBV(klines []binanceFapi.KLine) []float64 {
+	obv := make([]float64, len(klines))
+	for i := 1; i < len(klines); i++ {
+		switch {
+		case klines[i].Close > klines[i-1].Close:
+			obv[i] = obv[i-1] + klines[i].Volume
+		case klines[i].Close < klines[i-1].Close:
+			obv[i] = obv[i-1] - klines[i].Volume
+		default:
+			obv[i] = obv[i-1]
+		}
+	}
+	return obv
+}
+
+// 资金流乘数 ((C-L)-(H-C))/(H-L)
+func moneyFlowMultiplier(k binanceFapi.KLine) float64 {
+	if k.High == k.Low {
+		return 0
+	}
+	return ((k.Close - k.Low) - (k.High - k.Close)) / (k.High - k.Low)
+}
+
+// 计算 A/D 累积派发线序列
+func calculateAD(klines []binanceFapi.KLine) []float64 {
+	ad := make([]float64, len(klines))
+	var sum float64
+	for i, k := range klines {
+		sum += moneyFlowMultiplier(k) * k.Volume
+		ad[i] = sum
+	}
+	return ad
+}
+
+// 计算 CMF (蔡金资金流)
+func calculateCMF(klines []binanceFapi.KLine, period int) float64 {
+	n := len(klines)
+	if n < period {
+		return 0
+	}
+	var mfv, vol float64
+	for i := n - period; i < n; i++ {
+		mfv += moneyFlowMultiplier(klines[i]) * klines[i].Volume
+		vol += klines[i].Volume
+	}
+	if vol == 0 {
+		return 0
+	}
+	return mfv / vol
+}
+
+// 计算 MFI (资金流量指数)
+func calculateMFI(klines []binanceFapi.KLine, period int) float64 {
+	n := len(klines)
+	if n <= period {
+		return 50
+	}
+	var pos, neg float64
+	for i := n - period; i < n; i++ {
+		tp := (klines[i].High + klines[i].Low + klines[i].Close) / 3
+		prevTp := (klines[i-1].High + klines[i-1].Low + klines[i-1].Close) / 3
+		flow := tp * klines[i].Volume
+		if tp > prevTp {
+			pos += flow
+		} else if tp < prevTp {
+			neg += flow
+		}
+	}
+	if pos == 0 && neg == 0 {
+		return 50
+	}
+	if neg == 0 {
+		return 100
+	}
+	return 100 - 100/(1+pos/neg)
+}
+
+// OBV 与价格背离：回看区间内价格创新高/新低而 OBV 未同步
+func detectObvDivergence(closes, obv []float64, lookback int) int {
+	n := len(closes)
+	if n < lookback+1 {
+		return 0
+	}
+	priceHigh, priceLow := closes[n-lookback-1], closes[n-lookback-1]
+	obvHigh, obvLow := obv[n-lookback-1], obv[n-lookback-1]
+	for i := n - lookback; i < n-1; i++ {
+		priceHigh = max(priceHigh, closes[i])
+		priceLow = min(priceLow, closes[i])
+		obvHigh = max(obvHigh, obv[i])
+		obvLow = min(obvLow, obv[i])
+	}
+
+	if closes[n-1] > priceHigh && obv[n-1] < obvHigh {
+		return 2
+	}
+	if closes[n-1] < priceLow && obv[n-1] > obvLow {
+		return 1
+	}
+	return 0
+}
+
+// 计算量能指标
+func calculateVolumeFlow(klines []binanceFapi.KLine, params config.VolumeFlow) VolumeFlowResult {
+	n := len(klines)
+	res := VolumeFlowResult{}
+	if n < 2 {
+		return res
+	}
+	res.Enabled = true
+	if params.MfiPeriod <= 0 {
+		params.MfiPeriod = 14
+	}
+	if params.MfiTop <= 0 {
+		params.MfiTop = 80
+	}
+	if params.MfiLow <= 0 {
+		params.MfiLow = 20
+	}
+	if params.CmfPeriod <= 0 {
+		params.CmfPeriod = 20
+	}
+	if params.ObvLookback <= 0 {
+		params.ObvLookback = 20
+	}
+
+	obv := calculateOBV(klines)
+	res.Obv = obv[n-1]
+	res.Ad = calculateAD(klines)[n-1]
+	res.Cmf = calculateCMF(klines, params.CmfPeriod)
+	res.Mfi = calculateMFI(klines, params.MfiPeriod)
+
+	if res.Mfi >= float64(params.MfiTop) {
+		res.MfiZone = 1
+	} else if res.Mfi <= float64(params.MfiLow) {
+		res.MfiZone = 2
+	}
+	res.ObvDivergence = detectObvDivergence(binanceFapi.ClosePrice(klines), obv, params.ObvLookback)
+	return res
+}
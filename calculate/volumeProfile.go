@@ -0,0 +1,225 @@
+package calculate
+
+import (
+	"IndicatorTask/binanceFapi"
+	"IndicatorTask/config"
+	"time"
+)
+
+// UTC 交易时段 (每段 8 小时)
+var utcSessions = []struct {
+	Name  string
+	Start int // 开始小时 (UTC)
+}{
+	{"asia", 0},
+	{"europe", 8},
+	{"us", 16},
+}
+
+// VolumeProfile 成交量分布
+type VolumeProfile struct {
+	Session   string // window: 最近 Window 根K线; asia / europe / us: 最近一个完整 UTC 时段
+	StartTime int64  // 统计区间首根K线开盘时间 (ms)
+	EndTime   int64  // 统计区间末根K线开盘时间 (ms)
+	Low       float64
+	BinSize   float64
+	Bins      []float64
+	Poc       float64   // 成交量最大价位
+	Vah       float64   // 价值区上沿
+	Val       float64   // 价值区下沿
+	Lvns      []float64 // 低成交量节点价位
+}
+
+// VolumeProfileResult 成交量分布及信号
+type VolumeProfileResult struct {
+	Profiles    []VolumeProfile
+	OpenOutside int // 0: 无, 1: 时段开盘高于上一时段价值区, 2: 低于价值区
+	PocSignal   int // 0: 无, 1: 收复 POC, 2: 跌破 POC
+}
+
+// 按价格区间分箱统计成交量，每根K线成交量按与箱体重叠比例分配
+func buildVolumeProfile(klines []binanceFapi.KLine, bins int, valueArea, lvnRatio float64) (VolumeProfile, bool) {
+	if len(klines) == 0 || bins <= 0 {
+		return VolumeProfile{}, false
+	}
+	low, high := klines[0].Low, klines[0].High
+	for _, k := range klines {
+		low = min(low, k.Low)
+		high = max(high, k.High)
+	}
+	if high <= low {
+		return VolumeProfile{}, false
+	}
+
+	vp := VolumeProfile{
+		Low:       low,
+		BinSize:   (high - low) / float64(bins),
+		Bins:      make([]float64, bins),
+		StartTime: klines[0].OpenTime,
+		EndTime:   klines[len(klines)-1].OpenTime,
+	}
+	var total float64
+	for _, k := range klines {
+		if k.Volume <= 0 {
+			continue
+		}
+		total += k.Volume
+		span := k.High - k.Low
+		if span <= 0 {
+			idx := int((k.Close - low) / vp.BinSize)
+			if idx >= bins {
+				idx = bins - 1
+			}
+			vp.Bins[idx] += k.Volume
+			continue
+		}
+		first := int((k.Low - low) / vp.BinSize)
+		last := int((k.High - low) / vp.BinSize)
+		if last >= bins {
+			last = bins - 1
+		}
+		for b := first; b <= last; b++ {
+			binLow := low + float64(b)*vp.BinSize
+			overlap := min(k.High, binLow+vp.BinSize) - max(k.Low, binLow)
+			if overlap > 0 {
+				vp.Bins[b] += k.Volume * overlap / span
+			}
+		}
+	}
+	if total == 0 {
+		return VolumeProfile{}, false
+	}
+
+	// POC
+	poc := 0
+	for b := range vp.Bins {
+		if vp.Bins[b] > vp.Bins[poc] {
+			poc = b
+		}
+	}
+	vp.Poc = vp.binPrice(poc)
+
+	// 价值区：自 POC 向两侧扩展，每次并入成交量较大的一侧，直到覆盖 valueArea% 成交量
+	lo, hi := poc, poc
+	covered := vp.Bins[poc]
+	for covered < total*valueArea/100 && (lo > 0 || hi < bins-1) {
+		var below, above float64 = -1, -1
+		if lo > 0 {
+			below = vp.Bins[lo-1]
+		}
+		if hi < bins-1 {
+			above = vp.Bins[hi+1]
+		}
+		if above >= below {
+			hi++
+			covered += above
+		} else {
+			lo--
+			covered += below
+		}
+	}
+	vp.Val = low + float64(lo)*vp.BinSize
+	vp.Vah = low + float64(hi+1)*vp.BinSize
+
+	// 低成交量节点：低于平均箱体成交量 lvnRatio 倍的局部低点
+	avg := total / float64(bins)
+	for b := 1; b < bins-1; b++ {
+		if vp.Bins[b] < avg*lvnRatio && vp.Bins[b] <= vp.Bins[b-1] && vp.Bins[b] <= vp.Bins[b+1] {
+			vp.Lvns = append(vp.Lvns, vp.binPrice(b))
+		}
+	}
+	return vp, true
+}
+
+// 箱体中心价格
+func (vp VolumeProfile) binPrice(b int) float64 {
+	return vp.Low + (float64(b)+0.5)*vp.BinSize
+}
+
+// 所属 UTC 时段开始时间
+func sessionStart(openTime int64) (time.Time, string) {
+	t := time.UnixMilli(openTime).UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	start, name := day, utcSessions[0].Name
+	for _, s := range utcSessions {
+		if t.Hour() >= s.Start {
+			start, name = day.Add(time.Duration(s.Start)*time.Hour), s.Name
+		}
+	}
+	return start, name
+}
+
+// 计算成交量分布：最近 Window 根K线 (不含最新K线) 以及上一个完整 UTC 时段
+func calculateVolumeProfile(klines []binanceFapi.KLine, params config.VolumeProfile) VolumeProfileResult {
+	if params.Window <= 0 {
+		params.Window = 100
+	}
+	if params.Bins <= 0 {
+		params.Bins = 50
+	}
+	if params.ValueArea <= 0 {
+		params.ValueArea = 70
+	}
+	if params.LvnRatio <= 0 {
+		params.LvnRatio = 0.3
+	}
+	res := VolumeProfileResult{}
+	n := len(klines)
+	if n < 3 {
+		return res
+	}
+
+	start := n - 1 - params.Window
+	if start < 0 {
+		start = 0
+	}
+	window, ok := buildVolumeProfile(klines[start:n-1], params.Bins, params.ValueArea, params.LvnRatio)
+	if !ok {
+		return res
+	}
+	window.Session = "window"
+	res.Profiles = append(res.Profiles, window)
+
+	curr, prev := klines[n-1], klines[n-2]
+	if prev.Close < window.Poc && curr.Close > window.Poc {
+		res.PocSignal = 1
+	} else if prev.Close > window.Poc && curr.Close < window.Poc {
+		res.PocSignal = 2
+	}
+
+	// 时段分布仅对 8 小时以内的周期有意义
+	if !params.Sessions || klines[1].OpenTime-klines[0].OpenTime >= int64(8*time.Hour/time.Millisecond) {
+		return res
+	}
+	currStart, _ := sessionStart(curr.OpenTime)
+	prevStart, prevName := sessionStart(currStart.Add(-time.Minute).UnixMilli())
+	first, last := -1, -1
+	for i, k := range klines {
+		if k.OpenTime >= prevStart.UnixMilli() && k.OpenTime < currStart.UnixMilli() {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 || first == 0 {
+		// 上一时段数据不完整
+		return res
+	}
+	session, ok := buildVolumeProfile(klines[first:last+1], params.Bins, params.ValueArea, params.LvnRatio)
+	if !ok {
+		return res
+	}
+	session.Session = prevName
+	res.Profiles = append(res.Profiles, session)
+
+	// 最新K线为本时段第一根时，判断开盘是否在上一时段价值区之外
+	if curr.OpenTime == currStart.UnixMilli() {
+		if curr.Open > session.Vah {
+			res.OpenOutside = 1
+		} else if curr.Open < session.Val {
+			res.OpenOutside = 2
+		}
+	}
+	return res
+}
@@ -0,0 +1,161 @@
+package calculate
+
+import (
+	"IndicatorTask/binanceFapi"
+	"math"
+	"time"
+)
+
+// VWAP 锚点
+const (
+	VwapAnchorDay       = "日"
+	VwapAnchorWeek      = "周"
+	VwapAnchorSwingHigh = "前高"
+	VwapAnchorSwingLow  = "前低"
+	VwapAnchorFunding   = "结算"
+)
+
+// VwapBand 单个锚点的 VWAP 及标准差带
+type VwapBand struct {
+	Anchor     string
+	AnchorTime int64 // 锚点K线开盘时间 (ms)
+	Vwap       float64
+	Std        float64 // 1 倍标准差，N 倍带 = Vwap ± N*Std
+	Signal     int     // 0: 无, 1: 收复 VWAP, 2: 跌破 VWAP
+	BandTouch  int     // 0: 无, 1: 触及上轨, 2: 触及下轨
+}
+
+// VwapResult 各锚点 VWAP
+type VwapResult struct {
+	Bands []VwapBand
+}
+
+// 按锚点名称查找
+func (r VwapResult) Band(anchor string) (VwapBand, bool) {
+	for _, b := range r.Bands {
+		if b.Anchor == anchor {
+			return b, true
+		}
+	}
+	return VwapBand{}, false
+}
+
+// K线成交均价：优先使用成交额/成交量，缺失时使用典型价格
+func klineAvgPrice(k binanceFapi.KLine) float64 {
+	if k.Volume > 0 && k.QuoteVolume > 0 {
+		return k.QuoteVolume / k.Volume
+	}
+	return (k.High + k.Low + k.Close) / 3
+}
+
+// 计算从 start 到 end (含) 的 VWAP 与标准差
+func vwapRange(klines []binanceFapi.KLine, start, end int) (float64, float64) {
+	var sumV, sumPV, sumP2V float64
+	for i := start; i <= end; i++ {
+		p := klineAvgPrice(klines[i])
+		v := klines[i].Volume
+		sumV += v
+		sumPV += p * v
+		sumP2V += p * p * v
+	}
+	if sumV == 0 {
+		return 0, 0
+	}
+	vwap := sumPV / sumV
+	variance := sumP2V/sumV - vwap*vwap
+	if variance < 0 {
+		variance = 0
+	}
+	return vwap, math.Sqrt(variance)
+}
+
+// 锚点时间对应的第一根K线下标，早于数据范围返回 -1
+func anchorIndex(klines []binanceFapi.KLine, anchor time.Time) int {
+	ms := anchor.UnixMilli()
+	if len(klines) == 0 || klines[0].OpenTime > ms {
+		return -1
+	}
+	for i, k := range klines {
+		if k.OpenTime >= ms {
+			return i
+		}
+	}
+	return -1
+}
+
+// 计算单个锚点的 VWAP 及信号
+func anchoredVwap(klines []binanceFapi.KLine, anchor string, start int, bandTouch float64) (VwapBand, bool) {
+	n := len(klines)
+	// 至少需要两根K线判断收复/跌破
+	if start < 0 || start > n-2 {
+		return VwapBand{}, false
+	}
+
+	band := VwapBand{Anchor: anchor, AnchorTime: klines[start].OpenTime}
+	band.Vwap, band.Std = vwapRange(klines, start, n-1)
+	prevVwap, _ := vwapRange(klines, start, n-2)
+
+	curr := klines[n-1]
+	prev := klines[n-2]
+	if prev.Close < prevVwap && curr.Close > band.Vwap {
+		band.Signal = 1
+	} else if prev.Close > prevVwap && curr.Close < band.Vwap {
+		band.Signal = 2
+	}
+
+	if band.Std > 0 {
+		if curr.High >= band.Vwap+bandTouch*band.Std {
+			band.BandTouch = 1
+		} else if curr.Low <= band.Vwap-bandTouch*band.Std {
+			band.BandTouch = 2
+		}
+	}
+	return band, true
+}
+
+// 计算日、周、前高前低、资金费结算锚定 VWAP
+// nextFundingTime 为下次结算时间 (ms)，rateCycle 为结算周期 (小时)
+func calculateVwap(klines []binanceFapi.KLine, nextFundingTime int64, rateCycle int, bandTouch float64) VwapResult {
+	res := VwapResult{}
+	if len(klines) < 2 {
+		return res
+	}
+	if bandTouch <= 0 {
+		bandTouch = 3
+	}
+
+	now := time.UnixMilli(klines[len(klines)-1].CloseTime).UTC()
+
+	// UTC 日
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	// UTC 周 (周一开始)
+	weekday := (int(now.Weekday()) + 6) % 7
+	weekStart := dayStart.AddDate(0, 0, -weekday)
+
+	// 上次资金费结算
+	if rateCycle <= 0 {
+		rateCycle = 8
+	}
+	settleCycle := time.Duration(rateCycle) * time.Hour
+	lastSettle := now.Truncate(settleCycle)
+	if nextFundingTime > 0 {
+		lastSettle = time.UnixMilli(nextFundingTime).Add(-settleCycle)
+	}
+
+	anchors := []struct {
+		name  string
+		start int
+	}{
+		{VwapAnchorDay, anchorIndex(klines, dayStart)},
+		{VwapAnchorWeek, anchorIndex(klines, weekStart)},
+		{VwapAnchorSwingHigh, lastFractalIndex(klines, 1)},
+		{VwapAnchorSwingLow, lastFractalIndex(klines, 2)},
+		{VwapAnchorFunding, anchorIndex(klines, lastSettle)},
+	}
+	for _, a := range anchors {
+		if band, ok := anchoredVwap(klines, a.name, a.start, bandTouch); ok {
+			res.Bands = append(res.Bands, band)
+		}
+	}
+	return res
+}
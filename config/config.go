@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/fsnotify/fsnotify"
 )
@@ -18,13 +20,38 @@ type ServerConfig struct {
 	Api       Api              `json:"Api"`
 	Cycles    []CycleThreshold `json:"Cycles"`
 	Benchmark Benchmark        `json:"Benchmark"`
+	Baskets   []Basket         `json:"Baskets"`
+	Pairs     []Pair           `json:"Pairs"`
 	Database  DBConfig         `json:"Database"`
 }
 
 type CycleThreshold struct {
-	Cycle        string `json:"cycle"`
-	AlertCount   int    `json:"AlertCount"`   // 周期内触发次数
-	DelayMinutes int    `json:"DelayMinutes"` // 延时执行时间（分钟）
+	Cycle        string      `json:"cycle"`
+	AlertCount   int         `json:"AlertCount"`   // 周期内触发次数
+	DelayMinutes int         `json:"DelayMinutes"` // 延时执行时间（分钟）
+	Ichimoku     *Ichimoku   `json:"Ichimoku"`     // 周期自定义一目均衡表参数，为空时使用 Benchmark
+	Oscillator   *Oscillator `json:"Oscillator"`   // 周期自定义震荡指标开关及参数，为空时使用 Benchmark
+	Transform    *Transform  `json:"Transform"`    // 周期自定义 MACD/RSI/分型所用K线表示方式，为空时使用 Benchmark
+	Base         string      `json:"Base"`         // 自定义周期重采样所用的 Binance 基础周期，为空时自动选择
+	Topic        string      `json:"Topic"`        // 周期消息的 Telegram 话题，为空时按内置周期映射
+}
+
+// 自定义篮子指数，合成后以 Name 作为交易对名称入库和订阅
+type Basket struct {
+	Name      string             `json:"Name"`
+	Weighting string             `json:"Weighting"` // equal: 等权 (默认), marketcap: 按市值, volume: 按成交额
+	Symbols   []string           `json:"Symbols"`
+	Supply    map[string]float64 `json:"Supply"` // 按市值加权时各成分的流通量
+}
+
+// 配对价差监控，如 ETHUSDT/BTCUSDT
+type Pair struct {
+	Base   string  `json:"Base"`
+	Quote  string  `json:"Quote"`
+	Mode   string  `json:"Mode"`   // ratio: 价格比 (默认), spread: 按对冲比例调整的价差
+	Window int     `json:"Window"` // z-score、对冲比例及协整检验窗口，默认 100
+	Entry  float64 `json:"Entry"`  // |z| 突破该值时提示开仓，默认 2
+	Exit   float64 `json:"Exit"`   // |z| 回落到该值以内时提示平仓，默认 0.5
 }
 
 type DBConfig struct {
@@ -37,15 +64,38 @@ type DBConfig struct {
 }
 
 type Benchmark struct {
-	Macd   Macd `json:"Macd"`
-	Rsi    Rsi  `json:"Rsi"`
-	Klines int  `json:"Klines"`
+	Macd             Macd             `json:"Macd"`
+	Rsi              Rsi              `json:"Rsi"`
+	Ichimoku         Ichimoku         `json:"Ichimoku"`
+	Vwap             Vwap             `json:"Vwap"`
+	Supertrend       Supertrend       `json:"Supertrend"`
+	Sar              Sar              `json:"Sar"`
+	VolumeFlow       VolumeFlow       `json:"VolumeFlow"`
+	Oscillator       Oscillator       `json:"Oscillator"`
+	Divergence       Divergence       `json:"Divergence"`
+	Chan             Chan             `json:"Chan"`
+	Smc              Smc              `json:"Smc"`
+	Candle           Candle           `json:"Candle"`
+	ChartPattern     ChartPattern     `json:"ChartPattern"`
+	VolumeProfile    VolumeProfile    `json:"VolumeProfile"`
+	PivotPoint       PivotPoint       `json:"PivotPoint"`
+	Fibonacci        Fibonacci        `json:"Fibonacci"`
+	Confluence       Confluence       `json:"Confluence"`
+	RelativeStrength RelativeStrength `json:"RelativeStrength"`
+	Screener         Screener         `json:"Screener"`
+	Breadth          Breadth          `json:"Breadth"`
+	Cluster          Cluster          `json:"Cluster"`
+	Sector           Sector           `json:"Sector"`
+	Transform        Transform        `json:"Transform"`
+	Klines           int              `json:"Klines"`
 }
 
 type Macd struct {
-	FastPeriod int `json:"FastPeriod"`
-	SlowPeriod int `json:"SlowPeriod"`
-	Window     int `json:"Window"`
+	FastPeriod int     `json:"FastPeriod"`
+	SlowPeriod int     `json:"SlowPeriod"`
+	Window     int     `json:"Window"`
+	Histogram  bool    `json:"Histogram"` // 是否开启柱状图动能/面积分析
+	ZeroRatio  float64 `json:"ZeroRatio"` // MACD 线距0轴小于近期最大幅度的该比例时提示接近0轴，默认 0.1
 }
 
 type Rsi struct {
@@ -55,6 +105,202 @@ type Rsi struct {
 	Enable bool `json:"Enable"`
 }
 
+// 一目均衡表参数 (传统 9/26/52/26，加密市场常用 20/60/120/30)
+type Ichimoku struct {
+	Enable       bool `json:"Enable"`
+	Tenkan       int  `json:"Tenkan"`       // 转换线周期
+	Kijun        int  `json:"Kijun"`        // 基准线周期
+	SenkouB      int  `json:"SenkouB"`      // 先行带B周期
+	Displacement int  `json:"Displacement"` // 先行带/迟行线位移
+}
+
+// 锚定 VWAP 参数
+type Vwap struct {
+	Enable    bool    `json:"Enable"`
+	BandTouch float64 `json:"BandTouch"` // 触及外轨的标准差倍数，默认 3
+}
+
+// 超级趋势参数
+type Supertrend struct {
+	Enable     bool    `json:"Enable"`
+	AtrPeriod  int     `json:"AtrPeriod"`  // ATR 周期，默认 10
+	Multiplier float64 `json:"Multiplier"` // ATR 倍数，默认 3
+}
+
+// 抛物线 SAR 参数
+type Sar struct {
+	Enable  bool    `json:"Enable"`
+	Step    float64 `json:"Step"`    // 加速因子步长，默认 0.02
+	MaxStep float64 `json:"MaxStep"` // 加速因子上限，默认 0.2
+}
+
+// 量能指标参数 (OBV / MFI / CMF / A/D)
+type VolumeFlow struct {
+	Enable      bool `json:"Enable"`
+	MfiPeriod   int  `json:"MfiPeriod"`   // MFI 周期，默认 14
+	MfiTop      int  `json:"MfiTop"`      // MFI 超买阈值，默认 80
+	MfiLow      int  `json:"MfiLow"`      // MFI 超卖阈值，默认 20
+	CmfPeriod   int  `json:"CmfPeriod"`   // CMF 周期，默认 20
+	ObvLookback int  `json:"ObvLookback"` // OBV 背离回看K线数，默认 20
+}
+
+// 震荡指标组，各指标独立开关
+type Oscillator struct {
+	Kdj      Kdj   `json:"Kdj"`
+	Stoch    Stoch `json:"Stoch"`
+	StochRsi Stoch `json:"StochRsi"`
+	Cci      Band  `json:"Cci"`
+	Wr       Band  `json:"Wr"`
+}
+
+// KDJ 参数 (默认 9/3/3，J 值超过 JTop 或低于 JLow 视为极值)
+type Kdj struct {
+	Enable bool `json:"Enable"`
+	N      int  `json:"N"`
+	M1     int  `json:"M1"`
+	M2     int  `json:"M2"`
+	JTop   int  `json:"JTop"`
+	JLow   int  `json:"JLow"`
+}
+
+// 随机指标参数 (Stochastic 与 Stochastic RSI 共用，默认 14/3/3，80/20)
+type Stoch struct {
+	Enable  bool `json:"Enable"`
+	Period  int  `json:"Period"`
+	SmoothK int  `json:"SmoothK"`
+	SmoothD int  `json:"SmoothD"`
+	Top     int  `json:"Top"`
+	Low     int  `json:"Low"`
+}
+
+// 单线震荡指标参数 (CCI 默认 20/100/-100，威廉指标默认 14/-20/-80)
+type Band struct {
+	Enable bool `json:"Enable"`
+	Period int  `json:"Period"`
+	Top    int  `json:"Top"`
+	Low    int  `json:"Low"`
+}
+
+// 背离检测参数
+type Divergence struct {
+	Enable   bool `json:"Enable"`
+	Window   int  `json:"Window"`   // 波段点左右确认K线数，默认 5
+	Lookback int  `json:"Lookback"` // 回看K线数，默认 60
+}
+
+// 缠论结构参数
+type Chan struct {
+	Enable bool `json:"Enable"`
+	MinGap int  `json:"MinGap"` // 成笔时顶底分型在包含处理后K线上的最小间隔，默认 4
+}
+
+// SMC 结构分析参数
+type Smc struct {
+	Enable         bool    `json:"Enable"`
+	Window         int     `json:"Window"`         // 波段点左右确认K线数，默认 5
+	Lookback       int     `json:"Lookback"`       // 支撑压力回看K线数，默认 50
+	EqualTolerance float64 `json:"EqualTolerance"` // 等高/等低容差 (百分比)，默认 0.1
+}
+
+// K线形态识别参数
+type Candle struct {
+	Enable    bool    `json:"Enable"`
+	Lookback  int     `json:"Lookback"`  // 判断波段高低点的回看K线数，默认 10
+	Tolerance float64 `json:"Tolerance"` // 靠近支撑压力位的容差 (百分比)，默认 0.5
+}
+
+// 几何形态识别参数
+type ChartPattern struct {
+	Enable       bool    `json:"Enable"`
+	Window       int     `json:"Window"`       // 波段点左右确认K线数，默认 3
+	Lookback     int     `json:"Lookback"`     // 回看K线数，默认 120
+	Tolerance    float64 `json:"Tolerance"`    // 双顶/双底等价位容差 (百分比)，默认 1
+	FlatSlope    float64 `json:"FlatSlope"`    // 视为水平的边线斜率 (每根K线百分比)，默认 0.02
+	VolumeFactor float64 `json:"VolumeFactor"` // 突破放量倍数，默认 1.5
+}
+
+// 成交量分布参数
+type VolumeProfile struct {
+	Enable    bool    `json:"Enable"`
+	Window    int     `json:"Window"`    // 统计K线数，默认 100
+	Bins      int     `json:"Bins"`      // 价格分箱数，默认 50
+	ValueArea float64 `json:"ValueArea"` // 价值区成交量占比 (百分比)，默认 70
+	LvnRatio  float64 `json:"LvnRatio"`  // 低成交量节点阈值 (相对平均箱体成交量)，默认 0.3
+	Sessions  bool    `json:"Sessions"`  // 是否按 UTC 时段 (亚洲/欧洲/美洲) 统计
+}
+
+// 枢轴点参数
+type PivotPoint struct {
+	Enable     bool     `json:"Enable"`
+	Timeframes []string `json:"Timeframes"` // 高级别周期，默认 1d/1w/1M
+	Methods    []string `json:"Methods"`    // 计算方法 classic/fibonacci/camarilla/woodie，默认全部
+}
+
+// 斐波那契回撤参数
+type Fibonacci struct {
+	Enable   bool    `json:"Enable"`
+	Source   string  `json:"Source"`   // 波段来源 swing: SMC 波段高低点 (默认), chan: 缠论笔
+	Window   int     `json:"Window"`   // 波段高低点左右K线数，默认 5
+	Lookback int     `json:"Lookback"` // 回看K线数，默认 200
+	MinRange float64 `json:"MinRange"` // 显著波段的最小幅度 (%)，默认 3
+}
+
+// 多周期共振参数
+type Confluence struct {
+	Enable      bool    `json:"Enable"`
+	TrendPeriod int     `json:"TrendPeriod"` // 判断趋势的 EMA 周期
+	MinScore    float64 `json:"MinScore"`    // 共振得分百分比低于该值时不推送，0 表示不过滤
+}
+
+// 相对强弱参数
+type RelativeStrength struct {
+	Enable      bool     `json:"Enable"`
+	Benchmarks  []string `json:"Benchmarks"`  // 基准交易对，默认 BTCUSDT/ETHUSDT
+	Window      int      `json:"Window"`      // 收益、Beta、相关系数的滚动窗口
+	EmaPeriod   int      `json:"EmaPeriod"`   // 判断连续跑赢/跑输的 RS 线 EMA 周期
+	StreakAlert int      `json:"StreakAlert"` // 连续跑赢/跑输达到该K线数时提示，0 表示不提示
+}
+
+// 市场排行参数
+type Screener struct {
+	Enable bool `json:"Enable"`
+	TopN   int  `json:"TopN"` // 每个榜单保留的交易对数量，默认 5
+	Push   bool `json:"Push"` // 是否推送汇总消息到周期对应的 Telegram 话题
+}
+
+// 市场宽度参数
+type Breadth struct {
+	Enable           bool    `json:"Enable"`
+	HighLowPeriod    int     `json:"HighLowPeriod"`    // 新高新低的回看周期，默认 50
+	ExtremeHigh      float64 `json:"ExtremeHigh"`      // EMA50 上方占比高于该值 (%) 时提示，默认 80
+	ExtremeLow       float64 `json:"ExtremeLow"`       // EMA50 上方占比低于该值 (%) 时提示，默认 20
+	RsiExtreme       float64 `json:"RsiExtreme"`       // RSI 超买/超卖占比高于该值 (%) 时提示，默认 30
+	McClellanExtreme float64 `json:"McClellanExtreme"` // McClellan 绝对值超过该值时提示，默认 70
+}
+
+// 相关性聚类参数
+type Cluster struct {
+	Enable         bool    `json:"Enable"`
+	Window         int     `json:"Window"`         // 计算收益相关系数的K线数，默认 100
+	MinCorrelation float64 `json:"MinCorrelation"` // 簇间平均相关系数不低于该值时合并，默认 0.7
+	MergeAlerts    bool    `json:"MergeAlerts"`    // 同簇同向信号的说明只显示计数，不列出其他交易对
+}
+
+// 板块参数
+type Sector struct {
+	Enable bool   `json:"Enable"`
+	File   string `json:"File"`   // 板块分类文件 (config 目录下)，格式 {"DeFi": ["UNIUSDT", ...]}，默认 sectors.json
+	Window int    `json:"Window"` // 计算板块相对强弱的K线数，默认 20
+}
+
+// K线表示方式，MACD、RSI、分型及背离在转换后的K线上计算
+type Transform struct {
+	Type      string  `json:"Type"`      // 空: 原始K线, heikinashi: 平均K线, renko: 砖形图, range: 等幅K线
+	BoxSize   float64 `json:"BoxSize"`   // 砖块大小 (最新价百分比)，0 时使用 ATR
+	RangeSize float64 `json:"RangeSize"` // 等幅K线高低差 (最新价百分比)，0 时使用 ATR
+	AtrPeriod int     `json:"AtrPeriod"` // ATR 周期，默认 14
+}
+
 type Notify struct {
 	IsEnable         bool   `json:"IsEnable"`
 	Token            string `json:"Token"`
@@ -113,6 +359,44 @@ func LoadConfig(configNmae string) {
 	Cfg = &tmp
 }
 
+// 板块分类：板块 -> 交易对
+var Sectors map[string][]string
+
+// 交易对 -> 所属板块
+var symbolSectors map[string][]string
+
+// 加载板块分类文件
+func LoadSectors(fileName string) {
+	wd, _ := os.Getwd()
+	file, err := os.ReadFile(filepath.Join(wd, "config", fileName))
+	if err != nil {
+		panic("sector file error:" + err.Error())
+	}
+
+	file = bytes.TrimPrefix(file, []byte("\xef\xbb\xbf"))
+	var tmp map[string][]string
+	if err := json.Unmarshal(file, &tmp); err != nil {
+		panic("unmarshal json sector err:" + err.Error())
+	}
+	index := make(map[string][]string)
+	for sector, symbols := range tmp {
+		for _, symbol := range symbols {
+			symbol = strings.ToUpper(strings.TrimSpace(symbol))
+			index[symbol] = append(index[symbol], sector)
+		}
+	}
+	for _, sectors := range index {
+		sort.Strings(sectors)
+	}
+	Sectors = tmp
+	symbolSectors = index
+}
+
+// 交易对所属板块，未配置时为空
+func SymbolSectors(symbol string) []string {
+	return symbolSectors[symbol]
+}
+
 func WatchConfig(configName string) {
 	wd, _ := os.Getwd()
 	configDir := filepath.Join(wd, "config")
@@ -156,5 +440,11 @@ func WatchConfig(configName string) {
 
 func Init() {
 	LoadConfig("config.json")
+	if sector := Cfg.Benchmark.Sector; sector.Enable {
+		if sector.File == "" {
+			sector.File = "sectors.json"
+		}
+		LoadSectors(sector.File)
+	}
 	// go WatchConfig("config.json")
 }
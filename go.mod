@@ -7,6 +7,7 @@ require (
 	github.com/cryptoSelect/public v1.0.3
 	github.com/ethereum/go-ethereum v1.16.8
 	github.com/fsnotify/fsnotify v1.9.0
+	gorm.io/gorm v1.31.1
 )
 
 require (
@@ -22,5 +23,4 @@ require (
 	golang.org/x/sys v0.36.0 // indirect
 	golang.org/x/text v0.23.0 // indirect
 	gorm.io/driver/postgres v1.6.0 // indirect
-	gorm.io/gorm v1.31.1 // indirect
 )
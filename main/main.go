@@ -5,6 +5,7 @@ import (
 	"IndicatorTask/calculate"
 	"IndicatorTask/clean"
 	"IndicatorTask/config"
+	"IndicatorTask/store"
 	"IndicatorTask/utils/logger"
 	"IndicatorTask/utils/notify"
 	"context"
@@ -28,7 +29,13 @@ func main() {
 	logger.Init(config.Cfg.Mode)
 	db := config.Cfg.Database
 	database.InitDB(db.Host, db.User, db.Password, db.DBName, db.Port)
-	if err := database.AutoMigrate(&models.SymbolRecord{}, &models.UserInfo{}, &models.Subscription{}); err != nil {
+	if err := database.AutoMigrate(&models.SymbolRecord{}, &models.UserInfo{}, &models.Subscription{},
+		&store.IndicatorRecord{}, &store.DivergenceRecord{},
+		&store.ChanRecord{}, &store.ChanPointRecord{},
+		&store.VolumeProfileRecord{}, &store.PivotPointRecord{},
+		&store.RelativeStrengthRecord{}, &store.ScreenerRecord{},
+		&store.BreadthRecord{}, &store.ClusterRecord{},
+		&store.SectorRecord{}, &store.PairRecord{}); err != nil {
 		panic("failed to migrate database: " + err.Error())
 	}
 	clean.CleanNaNData()
@@ -64,6 +71,10 @@ func main() {
 
 	// 启动MACD计算周期
 	for _, c := range config.Cfg.Cycles {
+		if err := calculate.ValidateCycle(c.Cycle); err != nil {
+			logger.Log.Error("周期配置无效，已跳过", map[string]interface{}{"cycle": c.Cycle, "err": err.Error()})
+			continue
+		}
 		go calculate.MacdTicker(ctx, c.Cycle)
 	}
 	<-ctx.Done()
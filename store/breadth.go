@@ -0,0 +1,29 @@
+package store
+
+import "time"
+
+// BreadthRecord 市场宽度时间序列，每个周期每根K线一行
+type BreadthRecord struct {
+	ID            uint      `gorm:"primaryKey;comment:主键ID"`                              // 主键ID
+	Cycle         string    `gorm:"index:idx_breadth_cycle_time,unique;comment:周期"`       // 周期
+	BarTime       time.Time `gorm:"index:idx_breadth_cycle_time,unique;comment:统计K线开盘时间"` // 统计K线开盘时间
+	Total         int       `json:"total" gorm:"comment:参与统计的交易对数量"`                      // 交易对数量
+	Above20       *float64  `json:"above20" gorm:"comment:收盘在EMA20上方占比(%),K线不足时为空"`       // EMA20上方占比
+	Above50       *float64  `json:"above50" gorm:"comment:收盘在EMA50上方占比(%),K线不足时为空"`       // EMA50上方占比
+	Above200      *float64  `json:"above200" gorm:"comment:收盘在EMA200上方占比(%),K线不足时为空"`     // EMA200上方占比
+	Advances      int       `json:"advances" gorm:"comment:上涨家数"`                         // 上涨家数
+	Declines      int       `json:"declines" gorm:"comment:下跌家数"`                         // 下跌家数
+	AdLine        float64   `json:"ad_line" gorm:"comment:腾落线(累计净上涨家数)"`                  // 腾落线
+	NewHighs      int       `json:"new_highs" gorm:"comment:创N周期新高家数"`                    // 新高家数
+	NewLows       int       `json:"new_lows" gorm:"comment:创N周期新低家数"`                     // 新低家数
+	OversoldPct   float64   `json:"oversold_pct" gorm:"comment:RSI超卖占比(%)"`               // RSI超卖占比
+	OverboughtPct float64   `json:"overbought_pct" gorm:"comment:RSI超买占比(%)"`             // RSI超买占比
+	Ema19         float64   `json:"ema19" gorm:"comment:比例净上涨家数19周期EMA"`                  // 19周期EMA
+	Ema39         float64   `json:"ema39" gorm:"comment:比例净上涨家数39周期EMA"`                  // 39周期EMA
+	McClellan     float64   `json:"mcclellan" gorm:"comment:McClellan振荡器"`                // McClellan振荡器
+	CreatedAt     time.Time `json:"created_at" gorm:"comment:创建时间"`                       // 创建时间
+}
+
+func (BreadthRecord) TableName() string {
+	return "breadth_records"
+}
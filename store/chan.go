@@ -0,0 +1,30 @@
+package store
+
+import "time"
+
+// ChanRecord 缠论结构表，每个 symbol+cycle 一行，随每轮计算更新
+type ChanRecord struct {
+	ID     uint   `gorm:"primaryKey;comment:主键ID"`                        // 主键ID
+	Symbol string `gorm:"index:idx_chan_symbol_cycle,unique;comment:交易对"` // 交易对
+	Cycle  string `gorm:"index:idx_chan_symbol_cycle,unique;comment:周期"`  // 周期
+
+	StrokeCount    int       `json:"stroke_count" gorm:"comment:笔数量"`                 // 笔数量
+	LastStrokeDir  int       `json:"last_stroke_dir" gorm:"comment:最后一笔方向(1向上 2向下)"`  // 最后一笔方向
+	SegmentCount   int       `json:"segment_count" gorm:"comment:线段数量"`               // 线段数量
+	LastSegmentDir int       `json:"last_segment_dir" gorm:"comment:最后线段方向(1向上 2向下)"` // 最后线段方向
+	PivotCount     int       `json:"pivot_count" gorm:"comment:中枢数量"`                 // 中枢数量
+	PivotHigh      float64   `json:"pivot_high" gorm:"comment:最近中枢上沿ZG"`              // 最近中枢上沿
+	PivotLow       float64   `json:"pivot_low" gorm:"comment:最近中枢下沿ZD"`               // 最近中枢下沿
+	PivotGG        float64   `json:"pivot_gg" gorm:"comment:最近中枢最高点GG"`               // 最近中枢最高点
+	PivotDD        float64   `json:"pivot_dd" gorm:"comment:最近中枢最低点DD"`               // 最近中枢最低点
+	PivotStart     time.Time `json:"pivot_start" gorm:"comment:最近中枢开始时间"`             // 最近中枢开始时间
+	PivotEnd       time.Time `json:"pivot_end" gorm:"comment:最近中枢结束时间"`               // 最近中枢结束时间
+	PivotBreak     int       `json:"pivot_break" gorm:"comment:离开中枢(1向上 2向下)"`        // 离开中枢
+	Strokes        string    `json:"strokes" gorm:"type:text;comment:笔端点JSON"`        // 笔端点
+	Segments       string    `json:"segments" gorm:"type:text;comment:线段端点JSON"`      // 线段端点
+	UpdatedAt      time.Time `json:"updated_at" gorm:"comment:更新时间"`                  // 更新时间
+}
+
+func (ChanRecord) TableName() string {
+	return "chan_records"
+}
@@ -0,0 +1,23 @@
+package store
+
+import "time"
+
+// ChanPointRecord 缠论买卖点记录，每个新出现的买卖点一行
+type ChanPointRecord struct {
+	ID        uint      `gorm:"primaryKey;comment:主键ID"`                       // 主键ID
+	Symbol    string    `gorm:"index:idx_chan_point_symbol_cycle;comment:交易对"` // 交易对
+	Cycle     string    `gorm:"index:idx_chan_point_symbol_cycle;comment:周期"`  // 周期
+	Side      int       `json:"side" gorm:"comment:方向(1买点 2卖点)"`               // 方向
+	Class     int       `json:"class" gorm:"comment:类别(1/2/3类)"`               // 类别
+	Price     float64   `json:"price" gorm:"comment:买卖点价格"`                    // 买卖点价格
+	PointTime time.Time `json:"point_time" gorm:"comment:买卖点K线时间"`             // 买卖点K线时间
+	PivotHigh float64   `json:"pivot_high" gorm:"comment:参考中枢上沿ZG"`            // 参考中枢上沿
+	PivotLow  float64   `json:"pivot_low" gorm:"comment:参考中枢下沿ZD"`             // 参考中枢下沿
+	Area      float64   `json:"area" gorm:"comment:离开笔MACD面积(一类买卖点)"`          // 离开笔MACD面积
+	PrevArea  float64   `json:"prev_area" gorm:"comment:进入笔MACD面积(一类买卖点)"`     // 进入笔MACD面积
+	CreatedAt time.Time `json:"created_at" gorm:"comment:创建时间"`                // 创建时间
+}
+
+func (ChanPointRecord) TableName() string {
+	return "chan_point_records"
+}
@@ -0,0 +1,19 @@
+package store
+
+import "time"
+
+// ClusterRecord 相关性聚类表，每个 symbol+cycle 一行，随每轮计算更新
+type ClusterRecord struct {
+	ID             uint      `gorm:"primaryKey;comment:主键ID"`                                       // 主键ID
+	Symbol         string    `gorm:"index:idx_cluster_symbol_cycle,unique;comment:交易对"`             // 交易对
+	Cycle          string    `gorm:"index:idx_cluster_symbol_cycle,unique;comment:周期"`              // 周期
+	ClusterID      int       `json:"cluster_id" gorm:"comment:所属簇编号(按簇大小排序)"`                       // 所属簇编号
+	Size           int       `json:"size" gorm:"comment:簇内交易对数量"`                                   // 簇大小
+	AvgCorrelation float64   `json:"avg_correlation" gorm:"comment:与簇内其他交易对的平均相关系数"`                // 簇内平均相关系数
+	Peers          string    `json:"peers" gorm:"type:text;comment:相关性最高的交易对JSON [[交易对,相关系数],...]"` // 相关性最高的交易对
+	UpdatedAt      time.Time `json:"updated_at" gorm:"comment:更新时间"`                                // 更新时间
+}
+
+func (ClusterRecord) TableName() string {
+	return "cluster_records"
+}
@@ -0,0 +1,23 @@
+package store
+
+import "time"
+
+// DivergenceRecord 背离记录，每次新确认的背离一行
+type DivergenceRecord struct {
+	ID        uint      `gorm:"primaryKey;comment:主键ID"`                               // 主键ID
+	Symbol    string    `gorm:"index:idx_divergence_symbol_cycle;comment:交易对"`         // 交易对
+	Cycle     string    `gorm:"index:idx_divergence_symbol_cycle;comment:周期"`          // 周期
+	Indicator string    `json:"indicator" gorm:"comment:指标(RSI/MACD/HIST)"`            // 指标
+	Type      int       `json:"type" gorm:"comment:背离类型(1常规底背离 2常规顶背离 3隐藏底背离 4隐藏顶背离)"` // 背离类型
+	FromTime  time.Time `json:"from_time" gorm:"comment:第一个波段点时间"`                     // 第一个波段点时间
+	FromPrice float64   `json:"from_price" gorm:"comment:第一个波段点价格"`                    // 第一个波段点价格
+	FromValue float64   `json:"from_value" gorm:"comment:第一个波段点指标值"`                   // 第一个波段点指标值
+	ToTime    time.Time `json:"to_time" gorm:"comment:第二个波段点时间"`                       // 第二个波段点时间
+	ToPrice   float64   `json:"to_price" gorm:"comment:第二个波段点价格"`                      // 第二个波段点价格
+	ToValue   float64   `json:"to_value" gorm:"comment:第二个波段点指标值"`                     // 第二个波段点指标值
+	CreatedAt time.Time `json:"created_at" gorm:"comment:创建时间"`                        // 创建时间
+}
+
+func (DivergenceRecord) TableName() string {
+	return "divergence_records"
+}
@@ -0,0 +1,122 @@
+// Package store 定义本服务自有的指标结果表，与 public 库中的 SymbolRecord 按 symbol+cycle 一一对应
+package store
+
+import "time"
+
+// IndicatorRecord 扩展指标结果表，每个 symbol+cycle 一行，随每轮计算更新
+type IndicatorRecord struct {
+	ID     uint   `gorm:"primaryKey;comment:主键ID"`                             // 主键ID
+	Symbol string `gorm:"index:idx_indicator_symbol_cycle,unique;comment:交易对"` // 交易对
+	Cycle  string `gorm:"index:idx_indicator_symbol_cycle,unique;comment:周期"`  // 周期
+
+	// 一目均衡表
+	Tenkan        float64 `json:"tenkan" gorm:"comment:转换线"`                         // 转换线
+	Kijun         float64 `json:"kijun" gorm:"comment:基准线"`                          // 基准线
+	SenkouA       float64 `json:"senkou_a" gorm:"comment:先行带A(当前云)"`                 // 先行带A
+	SenkouB       float64 `json:"senkou_b" gorm:"comment:先行带B(当前云)"`                 // 先行带B
+	Chikou        float64 `json:"chikou" gorm:"comment:迟行线"`                         // 迟行线
+	CloudPosition int     `json:"cloud_position" gorm:"comment:价格与云关系(1云上 2云中 3云下)"` // 价格与云关系
+	TkCross       int     `json:"tk_cross" gorm:"comment:TK交叉(1金叉 2死叉)"`             // TK交叉
+	CloudTwist    int     `json:"cloud_twist" gorm:"comment:云扭转(1转多 2转空)"`           // 云扭转
+	ChikouConfirm int     `json:"chikou_confirm" gorm:"comment:迟行线确认(1多头 2空头)"`      // 迟行线确认
+
+	// 锚定 VWAP (标准差带 = VWAP ± N*Std)
+	VwapDay       float64 `json:"vwap_day" gorm:"comment:UTC日锚定VWAP"`
+	VwapDayStd    float64 `json:"vwap_day_std" gorm:"comment:UTC日VWAP标准差"`
+	VwapWeek      float64 `json:"vwap_week" gorm:"comment:UTC周锚定VWAP"`
+	VwapWeekStd   float64 `json:"vwap_week_std" gorm:"comment:UTC周VWAP标准差"`
+	VwapSwingHigh float64 `json:"vwap_swing_high" gorm:"comment:前高锚定VWAP"`
+	VwapSwingLow  float64 `json:"vwap_swing_low" gorm:"comment:前低锚定VWAP"`
+	VwapFunding   float64 `json:"vwap_funding" gorm:"comment:资金费结算锚定VWAP"`
+	VwapSignal    string  `json:"vwap_signal" gorm:"comment:VWAP信号"`
+
+	// 超级趋势 / 抛物线 SAR
+	SupertrendTrend int     `json:"supertrend_trend" gorm:"comment:超级趋势方向(1多头 2空头)"`
+	SupertrendStop  float64 `json:"supertrend_stop" gorm:"comment:超级趋势跟踪止损位"`
+	SupertrendFlip  int     `json:"supertrend_flip" gorm:"comment:超级趋势翻转(1翻多 2翻空)"`
+	SarTrend        int     `json:"sar_trend" gorm:"comment:SAR方向(1多头 2空头)"`
+	Sar             float64 `json:"sar" gorm:"comment:SAR跟踪止损位"`
+	SarFlip         int     `json:"sar_flip" gorm:"comment:SAR翻转(1翻多 2翻空)"`
+
+	// 量能指标
+	Obv           float64 `json:"obv" gorm:"comment:能量潮OBV"`
+	Mfi           float64 `json:"mfi" gorm:"comment:资金流量指数MFI"`
+	Cmf           float64 `json:"cmf" gorm:"comment:蔡金资金流CMF"`
+	Ad            float64 `json:"ad" gorm:"comment:累积派发线A/D"`
+	MfiZone       int     `json:"mfi_zone" gorm:"comment:MFI区域(1超买 2超卖)"`
+	ObvDivergence int     `json:"obv_divergence" gorm:"comment:OBV背离(1底背离 2顶背离)"`
+
+	// 震荡指标 (交叉: 1 K上穿D 2 K下穿D；区域: 1 超买 2 超卖)
+	KdjK          float64 `json:"kdj_k" gorm:"comment:KDJ-K"`
+	KdjD          float64 `json:"kdj_d" gorm:"comment:KDJ-D"`
+	KdjJ          float64 `json:"kdj_j" gorm:"comment:KDJ-J"`
+	KdjCross      int     `json:"kdj_cross" gorm:"comment:KDJ交叉"`
+	KdjZone       int     `json:"kdj_zone" gorm:"comment:KDJ J值极值区域"`
+	StochK        float64 `json:"stoch_k" gorm:"comment:随机指标K"`
+	StochD        float64 `json:"stoch_d" gorm:"comment:随机指标D"`
+	StochCross    int     `json:"stoch_cross" gorm:"comment:随机指标交叉"`
+	StochZone     int     `json:"stoch_zone" gorm:"comment:随机指标区域"`
+	StochRsiK     float64 `json:"stoch_rsi_k" gorm:"comment:随机RSI-K"`
+	StochRsiD     float64 `json:"stoch_rsi_d" gorm:"comment:随机RSI-D"`
+	StochRsiCross int     `json:"stoch_rsi_cross" gorm:"comment:随机RSI交叉"`
+	StochRsiZone  int     `json:"stoch_rsi_zone" gorm:"comment:随机RSI区域"`
+	Cci           float64 `json:"cci" gorm:"comment:CCI"`
+	CciZone       int     `json:"cci_zone" gorm:"comment:CCI区域"`
+	Wr            float64 `json:"wr" gorm:"comment:威廉指标%R"`
+	WrZone        int     `json:"wr_zone" gorm:"comment:威廉指标区域"`
+
+	// MACD 柱状图
+	MacdHist           float64 `json:"macd_hist" gorm:"comment:MACD柱"`
+	HistTrend          int     `json:"hist_trend" gorm:"comment:柱体趋势(1放大 2缩小)"`
+	HistStreak         int     `json:"hist_streak" gorm:"comment:连续放大/缩小根数"`
+	HistArea           float64 `json:"hist_area" gorm:"comment:当前区段面积"`
+	HistPrevArea       float64 `json:"hist_prev_area" gorm:"comment:前一同色区段面积"`
+	HistAreaDivergence int     `json:"hist_area_divergence" gorm:"comment:面积背离(1底背离 2顶背离)"`
+	ZeroApproach       int     `json:"zero_approach" gorm:"comment:MACD接近0轴(1自上方 2自下方)"`
+
+	// SMC (支撑压力、信号及区间字符串见 symbol_records)
+	SmcTrend   int     `json:"smc_trend" gorm:"comment:市场结构(1看涨 2看跌)"`
+	FvgType    int     `json:"fvg_type" gorm:"comment:FVG方向(1看涨 2看跌)"`
+	FvgLow     float64 `json:"fvg_low" gorm:"comment:FVG下沿"`
+	FvgHigh    float64 `json:"fvg_high" gorm:"comment:FVG上沿"`
+	ObType     int     `json:"ob_type" gorm:"comment:OB方向(1看涨 2看跌)"`
+	ObLow      float64 `json:"ob_low" gorm:"comment:OB下沿"`
+	ObHigh     float64 `json:"ob_high" gorm:"comment:OB上沿"`
+	EqualHighs float64 `json:"equal_highs" gorm:"comment:等高点价位"`
+	EqualLows  float64 `json:"equal_lows" gorm:"comment:等低点价位"`
+	Sweep      int     `json:"sweep" gorm:"comment:流动性扫荡(1扫下方 2扫上方)"`
+	RangeHigh  float64 `json:"range_high" gorm:"comment:交易区间高点"`
+	RangeLow   float64 `json:"range_low" gorm:"comment:交易区间低点"`
+	SmcZone    int     `json:"smc_zone" gorm:"comment:溢价折价区(1溢价 2折价 3平衡)"`
+
+	// K线形态
+	CandlePatterns string `json:"candle_patterns" gorm:"comment:最新收盘K线形态"`
+	CandleSignal   string `json:"candle_signal" gorm:"comment:关键位置K线形态信号"`
+
+	// 几何形态 (多个形态时优先记录已突破的)
+	ChartPattern  string  `json:"chart_pattern" gorm:"comment:几何形态"`
+	ChartUpper    float64 `json:"chart_upper" gorm:"comment:形态上突破位"`
+	ChartLower    float64 `json:"chart_lower" gorm:"comment:形态下突破位"`
+	ChartTarget   float64 `json:"chart_target" gorm:"comment:等幅测算目标价"`
+	ChartBreakout int     `json:"chart_breakout" gorm:"comment:突破方向(1向上 2向下)"`
+	ChartVolumeOk bool    `json:"chart_volume_ok" gorm:"comment:突破是否放量"`
+
+	// 斐波那契回撤
+	FibDirection  int     `json:"fib_direction" gorm:"comment:波段方向(1上涨 2下跌)"`
+	FibStart      float64 `json:"fib_start" gorm:"comment:波段起点价格"`
+	FibEnd        float64 `json:"fib_end" gorm:"comment:波段终点价格"`
+	FibPocketLow  float64 `json:"fib_pocket_low" gorm:"comment:黄金口袋下沿"`
+	FibPocketHigh float64 `json:"fib_pocket_high" gorm:"comment:黄金口袋上沿"`
+	FibInPocket   bool    `json:"fib_in_pocket" gorm:"comment:收盘是否位于黄金口袋"`
+
+	// 多周期共振
+	ConfluenceBias  int `json:"confluence_bias" gorm:"comment:信号方向(1看涨 2看跌)"`
+	ConfluenceScore int `json:"confluence_score" gorm:"comment:高级别周期共振得分"`
+	ConfluenceMax   int `json:"confluence_max" gorm:"comment:共振满分"`
+
+	UpdatedAt time.Time `json:"updated_at" gorm:"comment:更新时间"` // 更新时间
+}
+
+func (IndicatorRecord) TableName() string {
+	return "indicator_records"
+}
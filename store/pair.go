@@ -0,0 +1,26 @@
+package store
+
+import "time"
+
+// PairRecord 配对价差表，每个配对+cycle 一行，随每轮计算更新
+type PairRecord struct {
+	ID           uint      `gorm:"primaryKey;comment:主键ID"`                                               // 主键ID
+	Pair         string    `gorm:"index:idx_pair_cycle,unique;comment:配对(BASE/QUOTE)"`                    // 配对
+	Cycle        string    `gorm:"index:idx_pair_cycle,unique;comment:周期"`                                // 周期
+	Base         string    `json:"base" gorm:"comment:基础交易对"`                                             // 基础交易对
+	Quote        string    `json:"quote" gorm:"comment:对比交易对"`                                            // 对比交易对
+	Mode         string    `json:"mode" gorm:"comment:计算方式(ratio/spread)"`                                // 计算方式
+	HedgeRatio   float64   `json:"hedge_ratio" gorm:"comment:对冲比例"`                                       // 对冲比例
+	Spread       float64   `json:"spread" gorm:"comment:最新对数价差"`                                          // 最新价差
+	ZScore       float64   `json:"z_score" gorm:"comment:滚动Z-Score"`                                      // 滚动Z-Score
+	HalfLife     float64   `json:"half_life" gorm:"comment:均值回归半衰期(K线数)"`                                 // 半衰期
+	AdfStat      float64   `json:"adf_stat" gorm:"comment:价差Dickey-Fuller统计量"`                            // ADF统计量
+	Cointegrated bool      `json:"cointegrated" gorm:"comment:是否协整(5%: spread EG -3.34, ratio DF -2.86)"` // 是否协整
+	Signal       int       `json:"signal" gorm:"comment:信号(1做多价差 2做空价差 3平仓)"`                             // 信号
+	Position     int       `json:"position" gorm:"comment:持仓(0空仓 1做多价差 2做空价差)"`                           // 持仓
+	UpdatedAt    time.Time `json:"updated_at" gorm:"comment:更新时间"`                                        // 更新时间
+}
+
+func (PairRecord) TableName() string {
+	return "pair_records"
+}
@@ -0,0 +1,26 @@
+package store
+
+import "time"
+
+// PivotPointRecord 枢轴点表，每个 symbol+高级别周期+计算方法一行
+type PivotPointRecord struct {
+	ID          uint      `gorm:"primaryKey;comment:主键ID"`                                                                  // 主键ID
+	Symbol      string    `gorm:"index:idx_pivot_symbol_tf_method,unique;comment:交易对"`                                      // 交易对
+	Timeframe   string    `gorm:"index:idx_pivot_symbol_tf_method,unique;comment:高级别周期(1d/1w/1M)"`                          // 高级别周期
+	Method      string    `gorm:"index:idx_pivot_symbol_tf_method,unique;comment:计算方法(classic/fibonacci/camarilla/woodie)"` // 计算方法
+	PeriodStart time.Time `json:"period_start" gorm:"comment:计算所用K线开盘时间"`                                                   // 计算所用K线开盘时间
+	P           float64   `json:"p" gorm:"comment:中枢点P"`                                                                    // 中枢点
+	R1          float64   `json:"r1" gorm:"comment:阻力1"`                                                                    // 阻力1
+	R2          float64   `json:"r2" gorm:"comment:阻力2"`                                                                    // 阻力2
+	R3          float64   `json:"r3" gorm:"comment:阻力3"`                                                                    // 阻力3
+	R4          float64   `json:"r4" gorm:"comment:阻力4(仅Camarilla)"`                                                        // 阻力4
+	S1          float64   `json:"s1" gorm:"comment:支撑1"`                                                                    // 支撑1
+	S2          float64   `json:"s2" gorm:"comment:支撑2"`                                                                    // 支撑2
+	S3          float64   `json:"s3" gorm:"comment:支撑3"`                                                                    // 支撑3
+	S4          float64   `json:"s4" gorm:"comment:支撑4(仅Camarilla)"`                                                        // 支撑4
+	UpdatedAt   time.Time `json:"updated_at" gorm:"comment:更新时间"`                                                           // 更新时间
+}
+
+func (PivotPointRecord) TableName() string {
+	return "pivot_point_records"
+}
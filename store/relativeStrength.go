@@ -0,0 +1,26 @@
+package store
+
+import "time"
+
+// RelativeStrengthRecord 相对强弱表，每个 symbol+cycle+基准一行，随每轮计算更新
+type RelativeStrengthRecord struct {
+	ID          uint      `gorm:"primaryKey;comment:主键ID"`                              // 主键ID
+	Symbol      string    `gorm:"index:idx_rs_symbol_cycle_bench,unique;comment:交易对"`   // 交易对
+	Cycle       string    `gorm:"index:idx_rs_symbol_cycle_bench,unique;comment:周期"`    // 周期
+	Benchmark   string    `gorm:"index:idx_rs_symbol_cycle_bench,unique;comment:基准交易对"` // 基准交易对
+	Rs          float64   `json:"rs" gorm:"comment:相对强弱线(收盘价/基准收盘价)"`                   // 相对强弱线
+	RsChange    float64   `json:"rs_change" gorm:"index;comment:窗口内RS线涨跌幅(%),用于排名"`     // RS线涨跌幅
+	Return      float64   `json:"return" gorm:"comment:窗口内涨跌幅(%)"`                      // 涨跌幅
+	BenchReturn float64   `json:"bench_return" gorm:"comment:窗口内基准涨跌幅(%)"`              // 基准涨跌幅
+	Beta        float64   `json:"beta" gorm:"comment:滚动Beta"`                           // 滚动Beta
+	Correlation float64   `json:"correlation" gorm:"comment:滚动相关系数"`                    // 滚动相关系数
+	RsRsi       float64   `json:"rs_rsi" gorm:"comment:RS线RSI"`                         // RS线RSI
+	RsMacdHist  float64   `json:"rs_macd_hist" gorm:"comment:RS线MACD柱"`                 // RS线MACD柱
+	RsCross     int       `json:"rs_cross" gorm:"comment:RS线MACD交叉(1金叉 2死叉)"`           // RS线MACD交叉
+	Streak      int       `json:"streak" gorm:"comment:连续跑赢K线数(负数为跑输)"`                 // 连续跑赢K线数
+	UpdatedAt   time.Time `json:"updated_at" gorm:"comment:更新时间"`                       // 更新时间
+}
+
+func (RelativeStrengthRecord) TableName() string {
+	return "relative_strength_records"
+}
@@ -0,0 +1,18 @@
+package store
+
+import "time"
+
+// ScreenerRecord 市场排行快照表，每个周期每轮计算后整体替换
+type ScreenerRecord struct {
+	ID         uint      `gorm:"primaryKey;comment:主键ID"`                        // 主键ID
+	Cycle      string    `gorm:"index:idx_screener_cycle_category;comment:周期"`   // 周期
+	Category   string    `gorm:"index:idx_screener_cycle_category;comment:排行类别"` // 排行类别
+	Rank       int       `json:"rank" gorm:"comment:名次"`                         // 名次
+	Symbol     string    `json:"symbol" gorm:"comment:交易对"`                      // 交易对
+	Value      float64   `json:"value" gorm:"comment:排行数值"`                      // 排行数值
+	SnapshotAt time.Time `json:"snapshot_at" gorm:"comment:快照时间"`                // 快照时间
+}
+
+func (ScreenerRecord) TableName() string {
+	return "screener_records"
+}
@@ -0,0 +1,24 @@
+package store
+
+import "time"
+
+// SectorRecord 板块汇总时间序列，每个板块每个周期每根K线一行
+type SectorRecord struct {
+	ID               uint      `gorm:"primaryKey;comment:主键ID"`                                // 主键ID
+	Sector           string    `gorm:"index:idx_sector_cycle_time,unique;comment:板块"`          // 板块
+	Cycle            string    `gorm:"index:idx_sector_cycle_time,unique;comment:周期"`          // 周期
+	BarTime          time.Time `gorm:"index:idx_sector_cycle_time,unique;comment:统计K线开盘时间"`    // 统计K线开盘时间
+	Members          int       `json:"members" gorm:"comment:参与统计的交易对数量"`                      // 交易对数量
+	MedianChange     float64   `json:"median_change" gorm:"comment:涨跌幅中位数(%)"`                 // 涨跌幅中位数
+	MedianReturn     float64   `json:"median_return" gorm:"comment:窗口内涨跌幅中位数(%)"`              // 窗口涨跌幅中位数
+	RelativeStrength float64   `json:"relative_strength" gorm:"comment:相对全市场强弱(窗口涨跌幅中位数之差,%)"` // 相对强弱
+	Advancing        float64   `json:"advancing" gorm:"comment:上涨占比(%)"`                       // 上涨占比
+	AboveEma20       float64   `json:"above_ema20" gorm:"comment:收盘在EMA20上方占比(%)"`             // EMA20上方占比
+	VolumeShare      float64   `json:"volume_share" gorm:"comment:成交额占全市场比例(%)"`               // 成交额占比
+	Rotation         int       `json:"rotation" gorm:"comment:板块轮动(1转强 2转弱)"`                  // 板块轮动
+	CreatedAt        time.Time `json:"created_at" gorm:"comment:创建时间"`                         // 创建时间
+}
+
+func (SectorRecord) TableName() string {
+	return "sector_records"
+}
@@ -0,0 +1,23 @@
+package store
+
+import "time"
+
+// VolumeProfileRecord 成交量分布表，每个 symbol+cycle+统计区间一行，随每轮计算更新
+type VolumeProfileRecord struct {
+	ID        uint      `gorm:"primaryKey;comment:主键ID"`                                                      // 主键ID
+	Symbol    string    `gorm:"index:idx_vp_symbol_cycle_session,unique;comment:交易对"`                         // 交易对
+	Cycle     string    `gorm:"index:idx_vp_symbol_cycle_session,unique;comment:周期"`                          // 周期
+	Session   string    `gorm:"index:idx_vp_symbol_cycle_session,unique;comment:统计区间(window/asia/europe/us)"` // 统计区间
+	StartTime time.Time `json:"start_time" gorm:"comment:区间开始时间"`                                             // 区间开始时间
+	EndTime   time.Time `json:"end_time" gorm:"comment:区间最后一根K线时间"`                                           // 区间最后一根K线时间
+	Poc       float64   `json:"poc" gorm:"comment:成交量最大价位POC"`                                                // POC
+	Vah       float64   `json:"vah" gorm:"comment:价值区上沿VAH"`                                                  // VAH
+	Val       float64   `json:"val" gorm:"comment:价值区下沿VAL"`                                                  // VAL
+	Lvns      string    `json:"lvns" gorm:"type:text;comment:低成交量节点价位JSON"`                                   // 低成交量节点
+	Bins      string    `json:"bins" gorm:"type:text;comment:分箱成交量JSON [[价格,成交量],...]"`                       // 分箱成交量
+	UpdatedAt time.Time `json:"updated_at" gorm:"comment:更新时间"`                                               // 更新时间
+}
+
+func (VolumeProfileRecord) TableName() string {
+	return "volume_profile_records"
+}
@@ -18,34 +18,68 @@ import (
 
 const queueCap = 500
 
+// 通知类型
+const (
+	KindIndicator  = "indicator"  // 指标综合提醒
+	KindDivergence = "divergence" // 背离提醒
+	KindChanPoint  = "chan_point" // 缠论买卖点 (高优先级)
+	KindSector     = "sector"     // 板块轮动提醒
+	KindPair       = "pair"       // 配对价差提醒
+)
+
+// SectorPrefix 板块订阅的 symbol 前缀，如 SECTOR:DeFi
+const SectorPrefix = "SECTOR:"
+
 // NotifyJob 待发送的通知任务
 type NotifyJob struct {
 	Symbol  string
 	Cycle   string
+	Kind    string
 	Message string
 }
 
 var (
-	queue   chan NotifyJob
-	once    sync.Once
-	started bool
+	queue         chan NotifyJob
+	priorityQueue chan NotifyJob
+	once          sync.Once
+	started       bool
 )
 
 func initQueue() {
 	queue = make(chan NotifyJob, queueCap)
+	priorityQueue = make(chan NotifyJob, queueCap)
 }
 
-// Push 将通知任务放入队列（非阻塞，队列满则丢弃）
+// Push 将指标综合提醒放入队列（非阻塞，队列满则丢弃）
 func Push(symbol, cycle, message string) {
+	PushKind(symbol, cycle, KindIndicator, message)
+}
+
+// PushKind 将指定类型的通知任务放入队列（非阻塞，队列满则丢弃）
+func PushKind(symbol, cycle, kind, message string) {
 	once.Do(initQueue)
 	if !started {
-		logger.Log.Warn("notify worker not started, job dropped", map[string]interface{}{"symbol": symbol, "cycle": cycle})
+		logger.Log.Warn("notify worker not started, job dropped", map[string]interface{}{"symbol": symbol, "cycle": cycle, "kind": kind})
 		return
 	}
 	select {
-	case queue <- NotifyJob{Symbol: symbol, Cycle: cycle, Message: message}:
+	case queue <- NotifyJob{Symbol: symbol, Cycle: cycle, Kind: kind, Message: message}:
 	default:
-		logger.Log.Warn("notify queue full, job dropped", map[string]interface{}{"symbol": symbol, "cycle": cycle})
+		logger.Log.Warn("notify queue full, job dropped", map[string]interface{}{"symbol": symbol, "cycle": cycle, "kind": kind})
+	}
+}
+
+// PushPriority 将高优先级通知放入优先队列，Worker 总是先发送优先队列中的任务
+func PushPriority(symbol, cycle, kind, message string) {
+	once.Do(initQueue)
+	if !started {
+		logger.Log.Warn("notify worker not started, job dropped", map[string]interface{}{"symbol": symbol, "cycle": cycle, "kind": kind})
+		return
+	}
+	select {
+	case priorityQueue <- NotifyJob{Symbol: symbol, Cycle: cycle, Kind: kind, Message: message}:
+	default:
+		logger.Log.Warn("notify priority queue full, job dropped", map[string]interface{}{"symbol": symbol, "cycle": cycle, "kind": kind})
 	}
 }
 
@@ -55,10 +89,20 @@ func StartWorker(ctx context.Context) {
 	started = true
 	logger.Log.Info("notify worker started", nil)
 	for {
+		// 优先发送高优先级任务
+		select {
+		case job := <-priorityQueue:
+			sendToSubscribers(job)
+			continue
+		default:
+		}
+
 		select {
 		case <-ctx.Done():
 			logger.Log.Info("notify worker stopped", nil)
 			return
+		case job := <-priorityQueue:
+			sendToSubscribers(job)
 		case job, ok := <-queue:
 			if !ok {
 				return
@@ -79,17 +123,25 @@ func sendToSubscribers(job NotifyJob) {
 		return
 	}
 
-	// 查询：订阅了该 symbol+cycle 且已绑定 telegram 的用户
+	// 订阅目标：交易对本身及其所属板块 (板块名不区分大小写)
+	targets := []string{symbol}
+	if !strings.HasPrefix(symbol, SectorPrefix) {
+		for _, sector := range config.SymbolSectors(symbol) {
+			targets = append(targets, SectorPrefix+strings.ToUpper(sector))
+		}
+	}
+
+	// 查询：订阅了该 symbol (或所属板块)+cycle 且已绑定 telegram 的用户
 	var rows []struct {
 		TelegramID string `gorm:"column:telegram_id"`
 	}
 	err := database.DB.Raw(`
-		SELECT u.telegram_id FROM user_info u
+		SELECT DISTINCT u.telegram_id FROM user_info u
 		JOIN subscription s ON s.user_id = u.id
-		WHERE s.symbol = ? AND s.cycle = ? AND u.telegram_id IS NOT NULL AND TRIM(u.telegram_id) != ''
-	`, symbol, cycle).Scan(&rows).Error
+		WHERE UPPER(s.symbol) IN ? AND s.cycle = ? AND u.telegram_id IS NOT NULL AND TRIM(u.telegram_id) != ''
+	`, targets, cycle).Scan(&rows).Error
 	if err != nil {
-		logger.Log.Error("query subscribers failed", map[string]interface{}{"symbol": symbol, "err": err.Error()})
+		logger.Log.Error("query subscribers failed", map[string]interface{}{"symbol": symbol, "kind": job.Kind, "err": err.Error()})
 		return
 	}
 	telegramIDs := make([]string, 0, len(rows))
@@ -120,7 +172,7 @@ func sendToSubscribers(job NotifyJob) {
 		})
 		resp, err := http.Post(url, "application/json", bytes.NewBuffer(body))
 		if err != nil {
-			logger.Log.Error("send telegram failed", map[string]interface{}{"chat_id": chatID, "err": err.Error()})
+			logger.Log.Error("send telegram failed", map[string]interface{}{"chat_id": chatID, "kind": job.Kind, "err": err.Error()})
 			continue
 		}
 		resp.Body.Close()
@@ -31,6 +31,12 @@ func SendTelegramMessage(MsgTopic, Msg string) bool {
 	default:
 		topic = config.Cfg.Notify.InformationTopic
 	}
+	// 周期单独配置的话题优先 (自定义周期未配置时归入信息话题)
+	for _, c := range config.Cfg.Cycles {
+		if c.Cycle == MsgTopic && c.Topic != "" {
+			topic = c.Topic
+		}
+	}
 
 	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", config.Cfg.Notify.Token)
 	body, _ := json.Marshal(map[string]string{
@@ -42,6 +48,7 @@ func SendTelegramMessage(MsgTopic, Msg string) bool {
 	resp, err := http.Post(url, "application/json", bytes.NewBuffer(body))
 	if err != nil {
 		fmt.Println("send telegram message error:", err.Error())
+		return false
 	}
 	defer resp.Body.Close()
 	return true